@@ -6,88 +6,68 @@
 package app
 
 import (
-	"errors"
-	"path/filepath"
+	"net/http"
 
 	"github.com/issue9/logs"
-	"github.com/issue9/mux"
 	"github.com/issue9/web"
-	"github.com/issue9/web/encoding/html"
 
-	"github.com/caixw/gitype/client"
 	"github.com/caixw/gitype/path"
 )
 
 type app struct {
-	path *path.Path
-
-	// 当前是否正处在加载数据的状态，
-	// 防止在 reload 一次调用未完成的情况下，再次调用 reload
-	loading bool
-	client  *client.Client
-	html    *html.HTML
-	webhook *webhook
-	mux     *mux.Mux
+	sites []*site
 }
 
 // Run 运行程序
-func Run(path *path.Path, html *html.HTML, preview bool) error {
+//
+// 若 conf 目录下存在 sites.yaml，则根据其内容同时运行多个网站，
+// 否则 path 本身即为唯一的网站。
+func Run(path *path.Path, preview bool) error {
 	logs.Info("程序工作路径为:", path.Root)
 
-	a := &app{
-		path: path,
-		html: html,
-		mux:  web.Mux(),
+	sites, err := loadSites(path)
+	if err != nil {
+		return err
 	}
 
-	if preview {
-		watcher, err := a.initWatcher()
-		if err != nil {
-			return err
+	a := &app{sites: make([]*site, 0, len(sites))}
+	for _, s := range sites {
+		if err := s.init(preview); err != nil {
+			// 只有一个网站时，直接返回错误；
+			// 否则只记录错误，不影响其它网站的运行。
+			if len(sites) == 1 {
+				return err
+			}
+			logs.Error("网站 ", s.path.Root, " 初始化失败：", err)
+			continue
 		}
-		defer watcher.Close()
+		defer s.close()
 
-		a.watch(watcher)
-	} else {
-		conf := &webhook{}
-		p := filepath.Join(path.ConfDir, "webhook.yaml")
-		if err := web.LoadConfig(p, conf); err != nil {
-			return err
+		// 加载数据，此时出错，只记录错误信息，但不中断执行
+		if err := s.reload(); err != nil {
+			logs.Error(err)
 		}
-		a.webhook = conf
 
-		err := a.mux.HandleFunc(conf.URL, a.postWebhooks, conf.Method)
-		if err != nil {
-			return err
-		}
+		a.sites = append(a.sites, s)
 	}
 
-	// 加载数据，此时出错，只记录错误信息，但不中断执行
-	if err := a.reload(); err != nil {
-		logs.Error(err)
-	}
+	web.SetMiddleware(a.middleware)
 
 	return web.Serve()
 }
 
-// 重新加载数据
-func (a *app) reload() error {
-	if a.loading {
-		return errors.New("调用 reload 过于频繁")
-	}
-
-	a.loading = true
-	defer func() { a.loading = false }()
-
-	c, err := client.New(a.path)
-	if err != nil {
-		return err
-	}
+// 根据请求的域名将请求分发给对应的网站，
+// 找不到对应的网站时，交由 next 处理。
+func (a *app) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := hostname(r.Host)
+		for _, s := range a.sites {
+			if s.host == "" || s.host == host {
+				s.ServeHTTP(w, r)
+				return
+			}
+		}
 
-	// 只有新数据生成成功了，才会释放旧数据，并加载新数据到路由中。
-	if a.client != nil {
-		a.client.Free()
-	}
-	a.client = c
-	return a.client.Mount(a.mux, a.html)
+		next.ServeHTTP(w, r)
+	})
 }
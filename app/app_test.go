@@ -0,0 +1,27 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"os"
+	"testing"
+
+	"github.com/issue9/web"
+	"github.com/issue9/web/encoding"
+
+	"github.com/caixw/gitype/client/page"
+)
+
+func TestMain(m *testing.M) {
+	if err := web.Init("../testdata/conf"); err != nil {
+		panic(err)
+	}
+
+	if err := encoding.AddMarshal("text/html", page.Marshal); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
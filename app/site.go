@@ -0,0 +1,258 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/issue9/logs"
+	"github.com/issue9/mux"
+	"github.com/issue9/utils"
+	"github.com/issue9/web"
+	fsnotify "gopkg.in/fsnotify.v1"
+
+	"github.com/caixw/gitype/client"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+)
+
+// 多站点的配置文件名，位于 conf 目录下。
+const sitesFilename = "sites.yaml"
+
+// 表示一个网站，每个网站有自己的数据、路由、webhook 以及加载过程，
+// 某一个网站的加载失败不会影响其它网站。
+type site struct {
+	host string     // 网站的域名，为空表示匹配所有的域名
+	url  string     // 网站的地址，为空表示使用 web.yaml 中的配置
+	path *path.Path // 网站的工作目录
+
+	// 当前是否正处在加载数据的状态，
+	// 防止在 reload 一次调用未完成的情况下，再次调用 reload
+	loading bool
+	client  *client.Client
+	webhook *webhook
+	watcher *fsnotify.Watcher
+
+	// 每次加载数据都会生成新的 mux，加载成功之后替换旧的，
+	// 正在处理的请求依然使用旧的 mux 和 client。
+	mux *mux.Mux
+
+	// reload 与请求处理以及其它网站的 reload 处于不同的 goroutine，
+	// loading、client 和 mux 的读写都需要通过 locker。
+	locker sync.RWMutex
+}
+
+// sites.yaml 的内容
+type sitesConfig struct {
+	Sites []*siteConfig `yaml:"sites"`
+}
+
+type siteConfig struct {
+	// 网站的地址，比如 https://example.com/blog，
+	// 根据其中的域名匹配请求，路径部分则表示网站所在的子目录。
+	URL string `yaml:"url"`
+
+	// 网站的工作目录，与 appdir 的结构相同，但 conf 目录下只需要 webhook.yaml。
+	// 相对路径表示相对于 appdir。
+	Path string `yaml:"path"`
+}
+
+func (conf *sitesConfig) Sanitize() error {
+	if len(conf.Sites) == 0 {
+		return &helper.FieldError{Field: "sites", Message: "不能为空"}
+	}
+
+	hosts := make(map[string]bool, len(conf.Sites))
+	for index, s := range conf.Sites {
+		field := "sites[" + strconv.Itoa(index) + "]"
+
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			return &helper.FieldError{Field: field + ".url", Message: err.Error()}
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &helper.FieldError{Field: field + ".url", Message: "必须是包含域名的 http 或 https 地址"}
+		}
+
+		host := hostname(u.Host)
+		if hosts[host] {
+			return &helper.FieldError{Field: field + ".url", Message: "域名 %s 已经存在", Args: []interface{}{host}}
+		}
+		hosts[host] = true
+
+		if len(s.Path) == 0 {
+			return &helper.FieldError{Field: field + ".path", Message: "不能为空"}
+		}
+	}
+
+	return nil
+}
+
+// 加载所有的网站，若不存在 sites.yaml，则 p 本身即为唯一的网站。
+func loadSites(p *path.Path) ([]*site, error) {
+	file := filepath.Join(p.ConfDir, sitesFilename)
+	if !utils.FileExists(file) {
+		return []*site{newSite("", p)}, nil
+	}
+
+	conf := &sitesConfig{}
+	if err := web.LoadConfig(file, conf); err != nil {
+		return nil, err
+	}
+
+	sites := make([]*site, 0, len(conf.Sites))
+	for _, s := range conf.Sites {
+		dir := s.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(p.Root, dir)
+		}
+		sites = append(sites, newSite(s.URL, path.New(dir)))
+	}
+
+	return sites, nil
+}
+
+func newSite(siteURL string, p *path.Path) *site {
+	s := &site{
+		url:  siteURL,
+		path: p,
+	}
+
+	if u, err := url.Parse(siteURL); err == nil {
+		s.host = hostname(u.Host)
+	}
+
+	s.mux = s.newMux()
+
+	return s
+}
+
+func (s *site) newMux() *mux.Mux {
+	return mux.New(false, false, s.notFound, s.methodNotAllowed)
+}
+
+// 初始化文件监视器或是 webhook
+func (s *site) init(preview bool) error {
+	if preview {
+		watcher, err := s.initWatcher()
+		if err != nil {
+			return err
+		}
+		s.watcher = watcher
+
+		s.watch()
+		return nil
+	}
+
+	conf := &webhook{}
+	p := filepath.Join(s.path.ConfDir, "webhook.yaml")
+	if err := web.LoadConfig(p, conf); err != nil {
+		return err
+	}
+	s.webhook = conf
+
+	return s.mux.HandleFunc(conf.URL, s.postWebhooks, conf.Method)
+}
+
+func (s *site) close() {
+	if s.watcher != nil {
+		s.watcher.Close()
+	}
+}
+
+// 重新加载数据
+func (s *site) reload() error {
+	s.locker.Lock()
+	if s.loading {
+		s.locker.Unlock()
+		return errors.New("调用 reload 过于频繁")
+	}
+	s.loading = true
+	s.locker.Unlock()
+
+	defer func() {
+		s.locker.Lock()
+		s.loading = false
+		s.locker.Unlock()
+	}()
+
+	c, err := client.New(s.path, s.url)
+	if err != nil {
+		return err
+	}
+
+	m := s.newMux()
+	if s.webhook != nil {
+		if err = m.HandleFunc(s.webhook.URL, s.postWebhooks, s.webhook.Method); err != nil {
+			return err
+		}
+	}
+	if err = c.Mount(m); err != nil {
+		return err
+	}
+
+	// 只有新数据生成成功了，才会替换并释放旧数据。
+	s.locker.Lock()
+	old := s.client
+	s.client = c
+	s.mux = m
+	s.locker.Unlock()
+
+	if old != nil {
+		old.Free()
+	}
+	return nil
+}
+
+// 获取当前的 client，数据尚未成功加载时返回 nil。
+func (s *site) getClient() *client.Client {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+	return s.client
+}
+
+func (s *site) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.locker.RLock()
+	m := s.mux
+	s.locker.RUnlock()
+
+	m.ServeHTTP(w, r)
+}
+
+func (s *site) notFound(w http.ResponseWriter, r *http.Request) {
+	s.renderError(w, http.StatusNotFound)
+}
+
+func (s *site) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	s.renderError(w, http.StatusMethodNotAllowed)
+}
+
+// 数据尚未成功加载时，所有页面都输出 503。
+func (s *site) renderError(w http.ResponseWriter, code int) {
+	c := s.getClient()
+	if c == nil {
+		logs.Error("网站 ", s.path.Root, " 的数据未加载")
+		code = http.StatusServiceUnavailable
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	c.RenderError(w, code)
+}
+
+// 去掉 host 中的端口号
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/web/config"
+
+	"github.com/caixw/gitype/path"
+)
+
+var _ config.Sanitizer = &sitesConfig{}
+
+func TestSitesConfig_Sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	conf := &sitesConfig{}
+	a.Error(conf.Sanitize())
+
+	conf.Sites = []*siteConfig{
+		{URL: "https://example.com/blog", Path: "./blog"},
+		{URL: "http://example.org:8080", Path: "/srv/blog"},
+	}
+	a.NotError(conf.Sanitize())
+
+	// 不带域名
+	conf.Sites = []*siteConfig{{URL: "/blog", Path: "./blog"}}
+	a.Error(conf.Sanitize())
+
+	// 缺少 path
+	conf.Sites = []*siteConfig{{URL: "https://example.com"}}
+	a.Error(conf.Sanitize())
+
+	// 域名重复，端口号不作区分
+	conf.Sites = []*siteConfig{
+		{URL: "https://example.com/blog", Path: "./blog"},
+		{URL: "http://EXAMPLE.com:8080", Path: "./blog2"},
+	}
+	a.Error(conf.Sanitize())
+}
+
+func TestLoadSites(t *testing.T) {
+	a := assert.New(t)
+
+	// 不存在 sites.yaml，只有一个网站
+	p := path.New("../testdata")
+	sites, err := loadSites(p)
+	a.NotError(err).Equal(len(sites), 1)
+	a.Equal(sites[0].host, "").
+		Equal(sites[0].url, "").
+		Equal(sites[0].path, p).
+		NotNil(sites[0].mux)
+
+	s := newSite("https://Example.com:8080/blog", p)
+	a.Equal(s.host, "example.com").
+		Equal(s.url, "https://Example.com:8080/blog")
+}
+
+// 两个网站同时重新加载数据并处理请求，需要通过 go test -race 检测。
+func TestSite_reload(t *testing.T) {
+	a := assert.New(t)
+	p := path.New("../testdata")
+
+	ap := &app{sites: []*site{
+		newSite("http://example.com", p),
+		newSite("http://example.org", p),
+	}}
+	for _, s := range ap.sites {
+		a.NotError(s.reload()).NotNil(s.getClient())
+	}
+
+	h := ap.middleware(http.NotFoundHandler())
+	var wg sync.WaitGroup
+	for _, s := range ap.sites {
+		wg.Add(2)
+		done := make(chan struct{})
+
+		go func(s *site) {
+			defer wg.Done()
+			defer close(done)
+			for i := 0; i < 3; i++ {
+				if err := s.reload(); err != nil {
+					t.Error(err)
+				}
+			}
+		}(s)
+
+		// 在加载完成之前，一直发送请求
+		go func(s *site) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				for _, url := range []string{"/", "/not-exists"} {
+					w := httptest.NewRecorder()
+					h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://"+s.host+url, nil))
+					if w.Code != http.StatusOK && w.Code != http.StatusNotFound {
+						t.Errorf("%s%s 返回了 %d", s.host, url, w.Code)
+					}
+				}
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	// 正在加载时，不能再次加载
+	s := ap.sites[0]
+	s.locker.Lock()
+	s.loading = true
+	s.locker.Unlock()
+	a.Error(s.reload())
+}
+
+func TestHostname(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(hostname("example.com"), "example.com")
+	a.Equal(hostname("Example.com:8080"), "example.com")
+	a.Equal(hostname("[::1]:8080"), "::1")
+}
@@ -16,13 +16,13 @@ import (
 )
 
 // 初始化一个文件监视器
-func (a *app) initWatcher() (*fsnotify.Watcher, error) {
+func (s *site) initWatcher() (*fsnotify.Watcher, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
 	}
 
-	paths, err := recursivePaths(a.path)
+	paths, err := recursivePaths(s.path)
 	if err != nil {
 		watcher.Close()
 		return nil, err
@@ -60,17 +60,17 @@ func recursivePaths(path *path.Path) ([]string, error) {
 	return ret, nil
 }
 
-func (a *app) watch(watcher *fsnotify.Watcher) {
+func (s *site) watch() {
 	go func() {
 		for {
 			select {
-			case event := <-watcher.Events:
+			case event := <-s.watcher.Events:
 				if event.Op&fsnotify.Chmod == fsnotify.Chmod {
 					logs.Debug("watcher.Events:忽略 CHMOD 事件:", event)
 					continue
 				}
 
-				if time.Now().Sub(a.client.Created()) <= 1*time.Second { // 已经记录
+				if c := s.getClient(); c != nil && time.Now().Sub(c.Created()) <= 1*time.Second { // 已经记录
 					logs.Debug("watcher.Events:更新太频繁，该监控事件被忽略:", event)
 					continue
 				}
@@ -78,11 +78,11 @@ func (a *app) watch(watcher *fsnotify.Watcher) {
 				logs.Debug("watcher.Events:触发加载事件:", event)
 
 				go func() {
-					if err := a.reload(); err != nil {
+					if err := s.reload(); err != nil {
 						logs.Error(err) // 异步事件，直接输出错误日志
 					}
 				}()
-			case err := <-watcher.Errors:
+			case err := <-s.watcher.Errors:
 				logs.Error(err)
 				return // 出错就结束
 			} // end select
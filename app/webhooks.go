@@ -50,22 +50,22 @@ func (w *logWriter) Write(bs []byte) (int, error) {
 }
 
 // webhooks 的回调接口
-func (a *app) postWebhooks(w http.ResponseWriter, r *http.Request) {
+func (s *site) postWebhooks(w http.ResponseWriter, r *http.Request) {
 	logs.Trace("接收到 webhook 请求")
 
 	ctx := web.NewContext(w, r)
-	if time.Now().Sub(a.client.Created()) < a.webhook.Frequency {
+	if c := s.getClient(); c != nil && time.Now().Sub(c.Created()) < s.webhook.Frequency {
 		logs.Error("更新过于频繁，被中止！")
 		ctx.Exit(http.StatusTooManyRequests)
 	}
 
 	var cmd *exec.Cmd
-	if utils.FileExists(a.path.DataDir) {
+	if utils.FileExists(s.path.DataDir) {
 		cmd = exec.Command("git", "pull")
-		cmd.Dir = a.path.DataDir
+		cmd.Dir = s.path.DataDir
 	} else {
-		cmd = exec.Command("git", "clone", a.webhook.RepoURL, a.path.DataDir)
-		cmd.Dir = a.path.Root
+		cmd = exec.Command("git", "clone", s.webhook.RepoURL, s.path.DataDir)
+		cmd.Dir = s.path.Root
 	}
 
 	cmd.Stderr = (*logWriter)(logs.ERROR())
@@ -75,7 +75,7 @@ func (a *app) postWebhooks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := a.reload(); err != nil {
+	if err := s.reload(); err != nil {
 		ctx.Error(http.StatusInternalServerError, err)
 		return
 	}
@@ -14,10 +14,8 @@ import (
 	"github.com/issue9/logs"
 	"github.com/issue9/mux"
 	"github.com/issue9/utils"
-	"github.com/issue9/web"
 	"github.com/issue9/web/context"
 	"github.com/issue9/web/encoding"
-	"github.com/issue9/web/encoding/html"
 	"golang.org/x/text/message"
 
 	"github.com/caixw/gitype/client/page"
@@ -33,40 +31,75 @@ type Client struct {
 	mux  *mux.Mux
 
 	data     *data.Data
-	patterns []string // 记录所有的路由项，方便释放时删除
 	site     *page.Site
+	secret   []byte    // 受保护文章 cookie 的签名密钥
+	throttle *throttle // 受保护文章密码验证的频率限制，所有语言版本共用
+
+	// 其它语言版本，共用同一个路由，只有默认语言的 Client 才有值。
+	languages []*Client
 }
 
 // New 声明一个新的 Client 实例
-func New(path *path.Path) (*Client, error) {
-	d, err := data.Load(path)
+//
+// url 为网站的地址，为空时使用 web.yaml 中的配置。
+func New(path *path.Path, url string) (*Client, error) {
+	secret, err := loadSecret(path)
 	if err != nil {
 		return nil, err
 	}
 
-	client := &Client{
-		path: path,
-		data: d,
-		site: page.NewSite(d),
+	d, err := data.Load(path, url)
+	if err != nil {
+		return nil, err
+	}
+
+	t := newThrottle(passwordFailures, passwordWindow)
+	client := newClient(path, d, secret, t)
+
+	// d.Languages[0] 即为 d 本身
+	for index, ld := range d.Languages {
+		if index > 0 {
+			client.languages = append(client.languages, newClient(path, ld, secret, t))
+		}
 	}
 
 	return client, nil
 }
 
+func newClient(path *path.Path, d *data.Data, secret []byte, t *throttle) *Client {
+	return &Client{
+		path:     path,
+		data:     d,
+		site:     page.NewSite(d),
+		secret:   secret,
+		throttle: t,
+	}
+}
+
 // Mount 挂载路由以及数据
-func (client *Client) Mount(mux *mux.Mux, html *html.HTML) error {
+//
+// 页面使用当前数据中的主题模板进行渲染，需要将 page.Marshal 注册为 text/html 的编码函数。
+//
+// mux 的路由操作不是并发安全的，所以重新加载数据时，应该挂载到一个新的 mux 上，
+// 再以新的 mux 替换旧的，而不是在正在处理请求的 mux 上删除和添加路由。
+func (client *Client) Mount(mux *mux.Mux) error {
 	client.mux = mux
 
-	html.SetTemplate(client.data.Theme.Template)
-
 	// 为当前的语言注册一条数据
 	// 使当前语言能被正确解析
 	message.SetString(client.data.LanguageTag, "xx", "xx")
 
-	// 将所有的错误处理都指向同一个函数
-	web.SetErrorHandler(client.renderError, 0)
+	if err := client.initRoutes(); err != nil {
+		return err
+	}
+
+	for _, c := range client.languages {
+		if err := c.Mount(mux); err != nil {
+			return err
+		}
+	}
 
-	return client.initRoutes()
+	return nil
 }
 
 // Created 返回当前数据的创建时间
@@ -74,22 +107,18 @@ func (client *Client) Created() time.Time {
 	return client.data.Created
 }
 
-// Free 释放 Client 内容
+// Free 释放 Client 内容，包括所有的语言版本。
+//
+// 不会删除已经注册的路由，旧的 mux 可能还有未处理完的请求，
+// 由调用者在替换掉 mux 之后调用。
 func (client *Client) Free() {
-	for _, pattern := range client.patterns {
-		client.mux.Remove(pattern, http.MethodGet)
-	}
-	client.patterns = client.patterns[:0]
-
-	// 释放 data 数据
+	// 释放 data 数据，所有语言版本的数据都会被释放
 	client.data.Free()
 }
 
 // 每次访问前需要做的预处理工作。
 func (client *Client) prepare(f http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		logs.Tracef("%s: %s", r.UserAgent(), r.URL) // 输出访问日志
-
+	return client.recovery(func(w http.ResponseWriter, r *http.Request) {
 		// 直接根据整个博客的最后更新时间来确认 etag
 		if r.Header.Get("If-None-Match") == client.data.Etag {
 			logs.Tracef("304: %s", r.URL)
@@ -97,6 +126,36 @@ func (client *Client) prepare(f http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 		w.Header().Set("Etag", client.data.Etag)
+		f(w, r)
+	})
+}
+
+// 受保护文章的预处理工作
+//
+// 页面内容与 cookie 相关，不能使用整个博客共用的 etag，也不能被缓存。
+func (client *Client) prepareProtected(f http.HandlerFunc) http.HandlerFunc {
+	return client.recovery(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "private, no-store")
+		f(w, r)
+	})
+}
+
+// 输出访问日志，并处理由 exit 退出的请求。
+func (client *Client) recovery(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logs.Tracef("%s: %s", r.UserAgent(), r.URL) // 输出访问日志
+
+		// 由 exit 退出的，输出当前主题下对应的错误页面
+		defer func() {
+			if msg := recover(); msg != nil {
+				status, ok := msg.(httpStatus)
+				if !ok {
+					panic(msg)
+				}
+				client.RenderError(w, int(status))
+			}
+		}()
+
 		f(w, r)
 	}
 }
@@ -106,10 +165,21 @@ func (client *Client) page(ctx *context.Context, typ string) *page.Page {
 	return client.site.Page(ctx, typ, client.data)
 }
 
-// 输出一个特定状态码下的错误页面。
+// 表示一个 HTTP 状态码，由 exit 触发 panic，并在 prepare 中捕获。
+type httpStatus int
+
+// 以指定的状态码退出当前请求，并输出当前主题下对应的错误页面。
+//
+// 多个网站共用同一个进程时，web.SetErrorHandler 无法区分请求所属的网站，
+// 所以不使用 context.Exit。
+func (client *Client) exit(status int) {
+	panic(httpStatus(status))
+}
+
+// RenderError 输出一个特定状态码下的错误页面。
 // 若该页面模板不存在，则输出状态码对应的文本内容。
 // 只查找当前主题目录下的相关文件。
-func (client *Client) renderError(w http.ResponseWriter, code int) {
+func (client *Client) RenderError(w http.ResponseWriter, code int) {
 	logs.Debug("输出非正常状态码：", code)
 	var data []byte
 	var err error
@@ -10,8 +10,8 @@ import (
 
 	"github.com/issue9/web"
 	"github.com/issue9/web/encoding"
-	"github.com/issue9/web/encoding/html"
 
+	"github.com/caixw/gitype/client/page"
 	"github.com/caixw/gitype/path"
 )
 
@@ -21,19 +21,18 @@ func TestMain(m *testing.M) {
 	path := path.New("../testdata")
 	var err error
 
-	htmlMgr := html.New(nil)
-	encoding.AddMarshal("text/html", htmlMgr.Marshal)
+	encoding.AddMarshal("text/html", page.Marshal)
 
 	if err = web.Init(path.ConfDir); err != nil {
 		panic(err)
 	}
 
-	client, err = New(path)
+	client, err = New(path, "")
 	if err != nil {
 		panic(err)
 	}
 
-	if err = client.Mount(web.Mux(), htmlMgr); err != nil {
+	if err = client.Mount(web.Mux()); err != nil {
 		panic(err)
 	}
 
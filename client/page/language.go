@@ -0,0 +1,41 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package page
+
+import "github.com/caixw/gitype/data"
+
+// Language 表示当前页面的一个语言版本，
+// 用于生成语言切换菜单以及 <link rel="alternate" hreflang="..." />。
+type Language struct {
+	Tag        string // 语言标签，可直接用作 hreflang 的值
+	Name       string // 语言的名称，比如 English
+	URL        string // 当前页面在该语言下的完整地址，不存在对应的页面时，为该语言的首页
+	Current    bool   // 是否为当前页面的语言
+	Translated bool   // 该语言下是否存在与当前页面对应的页面
+}
+
+// Translate 生成当前页面在各个语言版本中的地址。
+//
+// d 为当前页面所在语言的数据；url 返回当前页面在某一语言中的地址，返回空值表示不存在。
+// 只有一种语言时，不做任何处理。
+func (p *Page) Translate(d *data.Data, url func(*data.Data) string) {
+	for _, ld := range d.Languages {
+		lang := &Language{
+			Tag:     ld.LanguageTag.String(),
+			Name:    ld.LanguageName,
+			Current: ld == d,
+		}
+
+		if u := url(ld); u != "" {
+			lang.URL = ld.Permalinks.AbsURL(u)
+			lang.Translated = true
+			p.Alternates = append(p.Alternates, lang)
+		} else {
+			lang.URL = ld.Permalinks.AbsURL(ld.Permalinks.PostsURL(1))
+		}
+
+		p.Languages = append(p.Languages, lang)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package page
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/issue9/web/encoding/html"
+)
+
+var errUnsupported = errors.New("当前不支持该对象的解析")
+
+// Marshal 针对 text/html 的 encoding.MarshalFunc 实现
+//
+// 与 html.HTML 使用固定的模板不同，Marshal 使用页面所在网站的主题模板，
+// 多个网站共用同一个进程时，可以分别使用各自的主题。
+func Marshal(v interface{}) ([]byte, error) {
+	tpl, ok := v.(*html.Template)
+	if !ok {
+		return nil, errUnsupported
+	}
+
+	p, ok := tpl.Data.(*Page)
+	if !ok {
+		return nil, errUnsupported
+	}
+
+	w := new(bytes.Buffer)
+	if err := p.Site.Theme.Template.ExecuteTemplate(w, tpl.Name, p); err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package page
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/web/encoding/html"
+
+	"github.com/caixw/gitype/data"
+)
+
+func TestMarshal(t *testing.T) {
+	a := assert.New(t)
+
+	newPage := func(text string) *Page {
+		tpl := template.Must(template.New("index").Parse(text))
+		return &Page{Site: &Site{Theme: &data.Theme{Template: tpl}}, Title: "title"}
+	}
+
+	// 不同的网站使用各自的模板
+	bs, err := Marshal(html.Tpl("index", newPage("t1:{{.Title}}")))
+	a.NotError(err).Equal(string(bs), "t1:title")
+
+	bs, err = Marshal(html.Tpl("index", newPage("t2:{{.Title}}")))
+	a.NotError(err).Equal(string(bs), "t2:title")
+
+	// 不存在的模板
+	bs, err = Marshal(html.Tpl("not-exists", newPage("")))
+	a.Error(err).Nil(bs)
+
+	// 不支持的类型
+	bs, err = Marshal(html.Tpl("index", "string"))
+	a.Error(err).Nil(bs)
+	bs, err = Marshal("string")
+	a.Error(err).Nil(bs)
+}
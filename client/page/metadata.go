@@ -0,0 +1,164 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package page
+
+import (
+	"strings"
+	"time"
+
+	"github.com/caixw/gitype/data"
+	"github.com/caixw/gitype/vars"
+)
+
+const schemaContext = "https://schema.org"
+
+// 根据页面的内容生成结构化的元数据
+func (p *Page) buildMetadata() *data.Metadata {
+	m := &data.Metadata{}
+
+	image := ""
+	if p.Post != nil && p.Post.Image != "" {
+		image = p.Site.Permalinks.AbsURL(p.Post.Image)
+	} else if p.Site.Icon != nil {
+		image = p.Site.Permalinks.AbsURL(p.Site.Icon.URL)
+	}
+
+	m.AddOpenGraph("og:site_name", p.Site.SiteName)
+	m.AddOpenGraph("og:title", p.Title)
+	m.AddOpenGraph("og:description", p.Description)
+	m.AddOpenGraph("og:url", p.Canonical)
+	m.AddOpenGraph("og:image", image)
+
+	card := "summary"
+	if p.Post != nil && p.Post.Image != "" {
+		card = "summary_large_image"
+	}
+	m.AddTwitter("twitter:card", card)
+	m.AddTwitter("twitter:title", p.Title)
+	m.AddTwitter("twitter:description", p.Description)
+	m.AddTwitter("twitter:image", image)
+
+	switch {
+	case p.Post != nil:
+		post := p.Post
+		m.AddOpenGraph("og:type", "article")
+		m.AddOpenGraph("article:published_time", post.Created.Format(time.RFC3339))
+		m.AddOpenGraph("article:modified_time", post.Modified.Format(time.RFC3339))
+		for _, author := range post.Authors {
+			m.AddOpenGraph("article:author", author.Name)
+		}
+		for _, tag := range post.Tags {
+			m.AddOpenGraph("article:tag", tag.Title)
+		}
+
+		m.JSONLD = append(m.JSONLD, p.blogPosting(image))
+	case p.Tag != nil:
+		m.AddOpenGraph("og:type", "website")
+		m.JSONLD = append(m.JSONLD, map[string]interface{}{
+			"@context":    schemaContext,
+			"@type":       "CollectionPage",
+			"name":        p.Tag.Title,
+			"description": p.Description,
+			"url":         p.Canonical,
+		})
+		if len(p.Tag.Breadcrumb) > 1 {
+			m.JSONLD = append(m.JSONLD, p.breadcrumbList())
+		}
+	default:
+		m.AddOpenGraph("og:type", "website")
+	}
+
+	if p.Type == vars.PageIndex {
+		m.JSONLD = append(m.JSONLD, p.webSite())
+	}
+
+	return m
+}
+
+// schema.org 中的 BlogPosting 类型
+func (p *Page) blogPosting(image string) map[string]interface{} {
+	post := p.Post
+
+	ld := map[string]interface{}{
+		"@context":         schemaContext,
+		"@type":            "BlogPosting",
+		"headline":         post.Title,
+		"description":      post.Summary,
+		"url":              p.Canonical,
+		"mainEntityOfPage": p.Canonical,
+		"datePublished":    post.Created.Format(time.RFC3339),
+		"dateModified":     post.Modified.Format(time.RFC3339),
+		"inLanguage":       post.Language,
+	}
+
+	if image != "" {
+		ld["image"] = image
+	}
+
+	if post.Keywords != "" {
+		ld["keywords"] = strings.Split(post.Keywords, ",")
+	}
+
+	authors := make([]map[string]interface{}, 0, len(post.Authors))
+	for _, a := range post.Authors {
+		author := map[string]interface{}{
+			"@type": "Person",
+			"name":  a.Name,
+		}
+		if a.URL != "" {
+			author["url"] = a.URL
+		}
+		authors = append(authors, author)
+	}
+	switch len(authors) {
+	case 0:
+	case 1:
+		ld["author"] = authors[0]
+	default:
+		ld["author"] = authors
+	}
+
+	return ld
+}
+
+// schema.org 中的 BreadcrumbList 类型，由标签的各级父标签组成。
+func (p *Page) breadcrumbList() map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(p.Tag.Breadcrumb))
+	for index, tag := range p.Tag.Breadcrumb {
+		items = append(items, map[string]interface{}{
+			"@type":    "ListItem",
+			"position": index + 1,
+			"name":     tag.Title,
+			"item":     p.Site.Permalinks.AbsURL(tag.Permalink),
+		})
+	}
+
+	return map[string]interface{}{
+		"@context":        schemaContext,
+		"@type":           "BreadcrumbList",
+		"itemListElement": items,
+	}
+}
+
+// schema.org 中的 WebSite 类型，若启用了 opensearch，则同时包含 SearchAction。
+func (p *Page) webSite() map[string]interface{} {
+	ld := map[string]interface{}{
+		"@context":   schemaContext,
+		"@type":      "WebSite",
+		"name":       p.Site.SiteName,
+		"url":        p.Site.URL,
+		"inLanguage": p.Site.Language,
+	}
+
+	if p.Site.Opensearch != nil {
+		ld["potentialAction"] = map[string]interface{}{
+			"@type":       "SearchAction",
+			"target":      p.Site.Permalinks.AbsURL(p.Site.Permalinks.SearchTemplateURL("{search_term_string}")),
+			"query-input": "required name=search_term_string",
+		}
+	}
+
+	return ld
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package page
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data"
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/vars"
+)
+
+func TestPage_buildMetadata(t *testing.T) {
+	a := assert.New(t)
+
+	site := &Site{SiteName: "site", URL: "https://example.com"}
+	p := &Page{
+		Site:      site,
+		Type:      vars.PagePost,
+		Title:     "title",
+		Canonical: "https://example.com/posts/1.html",
+		Post: &data.Post{
+			Title:    "title",
+			Image:    "https://example.com/1.png",
+			Keywords: "k1,k2",
+			Authors:  []*data.Author{{Author: loader.Author{Name: "caixw"}}},
+		},
+	}
+
+	m := p.buildMetadata()
+	a.NotNil(m)
+	a.Equal(len(m.JSONLD), 1)
+	a.Equal(m.JSONLD[0]["@type"], "BlogPosting")
+	a.Equal(m.JSONLD[0]["keywords"], []string{"k1", "k2"})
+	a.Equal(m.JSONLD[0]["image"], "https://example.com/1.png")
+	a.Equal(m.Twitter[0].Content, "summary_large_image")
+
+	// 首页，包含 WebSite，但未启用 opensearch
+	p = &Page{Site: site, Type: vars.PageIndex}
+	m = p.buildMetadata()
+	a.Equal(len(m.JSONLD), 1)
+	a.Equal(m.JSONLD[0]["@type"], "WebSite")
+	_, found := m.JSONLD[0]["potentialAction"]
+	a.False(found)
+	a.Equal(m.Twitter[0].Content, "summary")
+
+	// 标签页
+	p = &Page{Site: site, Type: vars.PageTag, Tag: &data.Tag{}}
+	m = p.buildMetadata()
+	a.Equal(m.JSONLD[0]["@type"], "CollectionPage")
+	a.Equal(len(m.JSONLD), 1)
+
+	// 子标签页，包含 BreadcrumbList
+	site.Permalinks = &data.Permalinks{}
+	parent := &data.Tag{Tag: loader.Tag{Title: "go"}, Permalink: "/tags/go.html"}
+	child := &data.Tag{Tag: loader.Tag{Title: "web"}, Permalink: "/tags/web.html"}
+	child.Breadcrumb = []*data.Tag{parent, child}
+	p = &Page{Site: site, Type: vars.PageTag, Tag: child}
+	m = p.buildMetadata()
+	a.Equal(len(m.JSONLD), 2)
+	a.Equal(m.JSONLD[1]["@type"], "BreadcrumbList")
+	items := m.JSONLD[1]["itemListElement"].([]map[string]interface{})
+	a.Equal(len(items), 2).
+		Equal(items[0]["item"], "/tags/go.html").
+		Equal(items[1]["position"], 2)
+}
@@ -28,15 +28,43 @@ type Page struct {
 	NextPage    *data.Link   // 下一页
 	Type        string       // 当前页面类型
 	Charset     string       // 当前页的字符集
-	Author      *data.Author // 作者
+	Author      *data.Author // 作者，作者详细页中为该页的作者
 	License     *data.Link   // 当前页的版本信息，可以为空
 
+	// 结构化的元数据，包括 Open Graph、Twitter Card 和 JSON-LD，
+	// 在 Render 时根据页面的其它内容生成，可通过模板函数 metadata 输出。
+	Metadata *data.Metadata
+
+	// 多语言时，当前页面的各个语言版本，由 Translate 生成。
+	// Languages 包含所有的语言，可用于切换语言；
+	// Alternates 只包含存在对应页面的语言，可用于输出 hreflang。
+	Languages  []*Language
+	Alternates []*Language
+
 	// 以下内容，仅在对应的页面才会有内容
-	Q        string          // 搜索关键字
-	Tag      *data.Tag       // 标签详细页面，非标签详细页，则为空
-	Posts    []*data.Post    // 文章列表，仅标签详情页和搜索页用到。
-	Post     *data.Post      // 文章详细内容，仅文章页面用到。
-	Archives []*data.Archive // 归档
+	Q        string              // 搜索关键字
+	Tag      *data.Tag           // 标签详细页面，非标签详细页，则为空
+	Posts    []*data.Post        // 文章列表，仅标签详情页、作者详情页和搜索页用到。
+	Post     *data.Post          // 文章详细内容，仅文章页面用到。
+	Single   *data.SinglePage    // 独立页面的内容，仅独立页面用到。
+	Archives []*data.Archive     // 归档
+	Period   *data.ArchivePeriod // 按年或是按月的存档页，非存档页，则为空
+
+	// 受保护文章的密码输入页，仅 protected 页面用到，此时 Post 为空。
+	Protected *Protected
+
+	// 所有年份的存档以及各年份下每个月的文章数量，可用于在侧边栏输出日历形式的存档
+	Calendar []*data.ArchivePeriod
+}
+
+// Protected 受保护文章的密码输入页所需的内容
+//
+// 只包含表单需要的字段，不包含文章的正文和目录等内容，
+// 避免主题在未验证时输出受保护的内容。
+type Protected struct {
+	Title     string // 文章标题
+	Permalink string // 文章地址，以 POST 方式将 password 字段提交到该地址即可验证密码
+	Error     bool   // 提交的密码是否错误
 }
 
 // Page 生成 Page 实例
@@ -49,6 +77,7 @@ func (site *Site) Page(ctx *context.Context, typ string, d *data.Data) *Page {
 		Type:     typ,
 		Author:   d.Author,
 		License:  d.License,
+		Calendar: d.Calendar,
 	}
 }
 
@@ -73,5 +102,6 @@ func (p *Page) Prev(url, text string) {
 // Render 渲染内容
 func (p *Page) Render(name string) {
 	p.Charset = p.context.OutputCharsetName
+	p.Metadata = p.buildMetadata()
 	p.context.Render(http.StatusOK, html.Tpl(name, p), nil)
 }
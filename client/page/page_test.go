@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/issue9/assert"
+	"golang.org/x/text/language"
+
+	"github.com/caixw/gitype/data"
 )
 
 func TestPage_Next(t *testing.T) {
@@ -37,3 +40,30 @@ func TestPage_Prev(t *testing.T) {
 	a.Equal(p.PrevPage.Rel, "prev")
 	a.Equal(p.PrevPage.Text, "text")
 }
+
+func TestPage_Translate(t *testing.T) {
+	a := assert.New(t)
+
+	// 只有一种语言
+	d := &data.Data{Permalinks: &data.Permalinks{}}
+	p := &Page{}
+	p.Translate(d, func(*data.Data) string { return "/tags.html" })
+	a.Empty(p.Languages).Empty(p.Alternates)
+
+	zh := &data.Data{LanguageTag: language.Chinese, LanguageName: "中文", Permalinks: &data.Permalinks{}}
+	en := &data.Data{LanguageTag: language.English, LanguageName: "English", Permalinks: &data.Permalinks{}}
+	zh.Languages = []*data.Data{zh, en}
+	en.Languages = zh.Languages
+
+	p = &Page{}
+	p.Translate(en, func(d *data.Data) string {
+		if d == en {
+			return "/en/tags.html"
+		}
+		return ""
+	})
+	a.Equal(len(p.Languages), 2).Equal(len(p.Alternates), 1)
+	a.Equal(p.Languages[0], &Language{Tag: "zh", Name: "中文", URL: "/"})
+	a.Equal(p.Languages[1], &Language{Tag: "en", Name: "English", URL: "/en/tags.html", Current: true, Translated: true})
+	a.Equal(p.Alternates[0], p.Languages[1])
+}
@@ -6,11 +6,13 @@ package page
 
 import (
 	"runtime"
+	"sort"
 	"time"
 
-	"github.com/issue9/web"
+	"golang.org/x/text/collate"
 
 	"github.com/caixw/gitype/data"
+	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/vars"
 )
 
@@ -35,11 +37,20 @@ type Site struct {
 	Atom          *data.Link
 	Opensearch    *data.Link
 	Manifest      *data.Link
-	ServiceWorker string       // 指向 service worker 的 js 文件
-	Tags          []*data.Tag  // 标签列表
-	Series        []*data.Tag  // 专题列表
-	Links         []*data.Link // 友情链接
-	Menus         []*data.Link // 导航菜单
+	Highlight     *data.Link     // 代码高亮的样式表，未启用代码高亮时为空
+	ServiceWorker string         // 指向 service worker 的 js 文件
+	Tags          []*data.Tag    // 标签列表
+	TagsByName    []*data.Tag    // 按标题排序的标签列表，排序规则与网站语言相关
+	TagsByCount   []*data.Tag    // 按文章数量从多到少排序的标签列表
+	TagsByRecent  []*data.Tag    // 按最新文章的创建时间从新到旧排序的标签列表
+	Series        []*data.Tag    // 专题列表
+	Authors       []*data.Author // authors.yaml 中的作者列表
+	Links         []*data.Link   // 友情链接
+	Menus         []*data.Link   // 导航菜单
+	Extra         loader.Extra   // config.yaml 中的自定义字段
+
+	// 各类页面地址的格式，模板中可以通过 .Site.Permalinks.TagsURL 等方法生成页面的地址
+	Permalinks *data.Permalinks
 }
 
 // NewSite 声明 Site 实例
@@ -53,7 +64,7 @@ func NewSite(d *data.Data) *Site {
 
 		SiteName:      d.SiteName,
 		Subtitle:      d.Subtitle,
-		URL:           web.URL(""),
+		URL:           d.Permalinks.SiteURL(),
 		Icon:          d.Icon,
 		Language:      d.LanguageTag.String(),
 		PostSize:      len(d.Posts),
@@ -63,10 +74,15 @@ func NewSite(d *data.Data) *Site {
 		ServiceWorker: d.ServiceWorkerPath,
 		Tags:          d.Tags,
 		Series:        d.Series,
+		Authors:       d.Authors,
 		Links:         d.Links,
 		Menus:         d.Menus,
+		Extra:         d.Extra,
+		Permalinks:    d.Permalinks,
 	}
 
+	site.TagsByName, site.TagsByCount, site.TagsByRecent = sortTags(d)
+
 	if d.RSS != nil {
 		site.RSS = &data.Link{
 			Title: d.RSS.Title,
@@ -99,5 +115,38 @@ func NewSite(d *data.Data) *Site {
 		}
 	}
 
+	if d.Highlight != nil {
+		site.Highlight = &data.Link{
+			URL:  d.Highlight.URL,
+			Type: d.Highlight.Type,
+		}
+	}
+
 	return site
 }
+
+// 生成按标题、文章数量和最新文章时间排序的标签列表，
+// 文章数量和时间相同的，再按标题排序。
+func sortTags(d *data.Data) (byName, byCount, byRecent []*data.Tag) {
+	c := collate.New(d.LanguageTag)
+
+	byName = make([]*data.Tag, len(d.Tags))
+	copy(byName, d.Tags)
+	sort.SliceStable(byName, func(i, j int) bool {
+		return c.CompareString(byName[i].Title, byName[j].Title) < 0
+	})
+
+	byCount = make([]*data.Tag, len(byName))
+	copy(byCount, byName)
+	sort.SliceStable(byCount, func(i, j int) bool {
+		return byCount[i].PostCount > byCount[j].PostCount
+	})
+
+	byRecent = make([]*data.Tag, len(byName))
+	copy(byRecent, byName)
+	sort.SliceStable(byRecent, func(i, j int) bool {
+		return byRecent[i].LastPosted.After(byRecent[j].LastPosted)
+	})
+
+	return byName, byCount, byRecent
+}
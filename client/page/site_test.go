@@ -0,0 +1,34 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package page
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+	"golang.org/x/text/language"
+
+	"github.com/caixw/gitype/data"
+	"github.com/caixw/gitype/data/loader"
+)
+
+func TestSortTags(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+
+	t1 := &data.Tag{Tag: loader.Tag{Title: "b"}, PostCount: 1, LastPosted: now}
+	t2 := &data.Tag{Tag: loader.Tag{Title: "c"}, PostCount: 3, LastPosted: now.Add(-time.Hour)}
+	t3 := &data.Tag{Tag: loader.Tag{Title: "a"}, PostCount: 1, LastPosted: now.Add(-2 * time.Hour)}
+	d := &data.Data{LanguageTag: language.English, Tags: []*data.Tag{t1, t2, t3}}
+
+	byName, byCount, byRecent := sortTags(d)
+	a.Equal(byName, []*data.Tag{t3, t1, t2})
+	a.Equal(byCount, []*data.Tag{t2, t3, t1}) // 数量相同的按标题排序
+	a.Equal(byRecent, []*data.Tag{t1, t2, t3})
+
+	// 不会改变原来的顺序
+	a.Equal(d.Tags, []*data.Tag{t1, t2, t3})
+}
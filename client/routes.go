@@ -5,12 +5,17 @@
 package client
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/issue9/logs"
+	"github.com/issue9/mux"
 	"github.com/issue9/web"
 	"github.com/issue9/web/context"
 
+	"github.com/caixw/gitype/client/page"
 	"github.com/caixw/gitype/data"
 	"github.com/caixw/gitype/vars"
 )
@@ -26,26 +31,85 @@ func (client *Client) initRoutes() error {
 			return
 		}
 
-		client.patterns = append(client.patterns, pattern)
 		err = client.mux.HandleFunc(pattern, client.prepare(h), http.MethodGet)
 	}
 
-	handle(vars.PostURL("{slug}"), client.getPost)   // posts/2016/about.html   posts/{slug}.html
-	handle(vars.AssetURL("{path}"), client.getAsset) // posts/2016/about/abc.png  posts/{path}
-	handle(vars.IndexURL(0), client.getPosts)        // index.html
-	handle(vars.LinksURL(), client.getLinks)         // links.html
-	handle(vars.TagURL("{slug}", 1), client.getTag)  // tags/tag1.html     tags/{slug}.html
-	handle(vars.TagsURL(), client.getTags)           // tags.html
-	handle(vars.ArchivesURL(), client.getArchives)   // archives.html
-	handle(vars.SearchURL("", 1), client.getSearch)  // search.html
-	handle(vars.ThemeURL("{path}"), client.getTheme) // themes/...          themes/{path}
-	handle("/{path}", client.getRaw)                 // /...                /{path}
+	// 以路径的形式分页时，列表页需要额外注册分页的路由项
+	handleList := func(url string, h http.HandlerFunc) {
+		handle(url, h)
+		if pattern := client.data.Permalinks.PageRoute(url); pattern != "" {
+			handle(pattern, h)
+		}
+	}
+
+	// 受保护的文章需要以 POST 提交密码
+	handleProtected := func(pattern string, h http.HandlerFunc) {
+		if err != nil {
+			return
+		}
+
+		err = client.mux.HandleFunc(pattern, client.prepareProtected(h), http.MethodGet, http.MethodPost)
+	}
+
+	urls := client.data.Permalinks
+
+	// 文章和标签的地址格式可以自定义，且 slug 中可能包含 /，
+	// 所以直接以各自的地址注册路由，而不是使用带参数的路由项。
+	for index, post := range client.data.Posts {
+		if post.Protection != nil {
+			handleProtected(post.Permalink, client.getProtectedPost(index))
+			continue
+		}
+		handle(post.Permalink, client.getPost(index)) // posts/2016/about.html
+	}
+	for _, page := range client.data.SinglePages {
+		handle(page.Permalink, client.getSinglePage(page)) // pages/about.html
+	}
+	for _, tags := range [][]*data.Tag{client.data.Tags, client.data.Series} {
+		for _, tag := range tags {
+			handleList(tag.Permalink, client.getTag(tag)) // tags/tag1.html
+		}
+	}
+	for _, year := range client.data.Calendar {
+		handleList(year.Permalink, client.getArchive(year)) // archives/2018.html
+		for _, month := range year.Months {
+			handleList(month.Permalink, client.getArchive(month)) // archives/2018/01.html
+		}
+	}
+	for _, author := range client.data.Authors {
+		handleList(author.Permalink, client.getAuthor(author)) // authors/caixw.html
+	}
+	if len(client.data.Authors) > 0 {
+		handle(urls.AuthorsURL(), client.getAuthors) // authors.html
+	}
+
+	handle(urls.AssetURL("{path}"), client.getAsset)    // posts/2016/about/abc.png  posts/{path}
+	handleList(urls.IndexURL(0), client.getPosts)       // index.html
+	handle(urls.LinksURL(), client.getLinks)            // links.html
+	handle(urls.TagsURL(), client.getTags)              // tags.html
+	handle(urls.ArchivesURL(), client.getArchives)      // archives.html
+	handleList(urls.SearchURL("", 0), client.getSearch) // search.html
+	handle(urls.ThemeURL("{path}"), client.getTheme)    // themes/...          themes/{path}
+	handle(urls.ImageURL("{path}"), client.getImage)    // images/...          images/{path}
+	handle(urls.URL("/{path}"), client.getRaw)          // /...                /{path}
 
 	// 根据配置决定是否有 sw.js
 	if client.data.ServiceWorkerPath != "" {
 		handle(client.data.ServiceWorkerPath, client.getServiceWorker) // /sw.js
 	}
 
+	// 部署在子目录下时，访问 /blog 跳转到 /blog/
+	if root := urls.Root(); root != "" {
+		handle(root, func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, urls.PostsURL(1), http.StatusMovedPermanently)
+		})
+	}
+
+	// 重定向的地址都是固定的，优先级高于以上带参数的路由项。
+	for _, redirect := range client.data.Redirects {
+		handle(redirect.Source, client.redirect(redirect))
+	}
+
 	return err
 }
 
@@ -55,7 +119,6 @@ func (client *Client) initFeedRoutes() (err error) {
 			return
 		}
 
-		client.patterns = append(client.patterns, feed.URL)
 		err = client.mux.HandleFunc(feed.URL, client.prepare(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", feed.Type)
 			w.Write(feed.Content)
@@ -67,6 +130,10 @@ func (client *Client) initFeedRoutes() (err error) {
 	handle(client.data.Sitemap)
 	handle(client.data.Opensearch)
 	handle(client.data.Manifest)
+	handle(client.data.Highlight)
+	for _, author := range client.data.Authors {
+		handle(author.Feed)
+	}
 
 	return err
 }
@@ -80,39 +147,36 @@ func (client *Client) getServiceWorker(w http.ResponseWriter, r *http.Request) {
 	w.Write(client.data.ServiceWorker)
 }
 
-// 文章详细页
-// /posts/{slug}.html
-func (client *Client) getPost(w http.ResponseWriter, r *http.Request) {
-	ctx := web.NewContext(w, r)
-	slug, err := ctx.ParamString("slug")
-	if err != nil {
-		logs.Error(err)
-		client.getAsset(w, r)
-		return
-	}
-
-	index := -1
-	for i, p := range client.data.Posts {
-		if p.Slug == slug {
-			index = i
-			break
+// 重定向，状态码为 410 时输出错误页面
+func (client *Client) redirect(redirect *data.Redirect) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if redirect.Status == http.StatusGone {
+			client.RenderError(w, http.StatusGone)
+			return
 		}
+
+		http.Redirect(w, r, redirect.Target, redirect.Status)
 	}
+}
 
-	if index < 0 {
-		logs.Debugf("并未找到与之相对应的文章：%s", slug)
-		client.getRaw(w, r) // 文章不存在，则查找 raws 目录下是否存在同名文件
-		return
+// 文章详细页
+// /posts/{slug}.html
+func (client *Client) getPost(index int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client.renderPost(web.NewContext(w, r), index)
 	}
+}
 
-	post := client.data.Posts[index]
+// 受保护的文章，需要在验证通过之后才能调用。
+func (client *Client) renderPost(ctx *context.Context, index int) {
+	post := client.data.Posts[index].Unlocked()
 	p := client.page(ctx, vars.PagePost)
 
 	p.Post = post
 	p.Keywords = post.Keywords
 	p.Description = post.Summary
 	p.Title = post.HTMLTitle
-	p.Canonical = web.URL(post.Permalink)
+	p.Canonical = client.data.Permalinks.AbsURL(post.Permalink)
 	p.License = post.License // 文章可具体指定协议
 	p.Author = post.Author   // 文章可具体指定作者
 
@@ -125,18 +189,106 @@ func (client *Client) getPost(w http.ResponseWriter, r *http.Request) {
 		p.Next(next.Permalink, next.Title)
 	}
 
+	p.Translate(client.data, func(d *data.Data) string {
+		if t := post.Translation(d); t != nil {
+			return t.Permalink
+		}
+		return ""
+	})
+
 	p.Render(post.Template)
 }
 
+// 受保护的文章，未通过验证时输出密码输入页
+// /posts/{slug}.html
+//
+// 密码以 POST 方式提交到文章的地址，无论验证是否通过，都会跳转回文章页。
+// 同一客户端失败次数过多时，返回 429。
+func (client *Client) getProtectedPost(index int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		post := client.data.Posts[index]
+		protection := post.Protection
+
+		if r.Method == http.MethodPost {
+			key := throttleKey(r, protection.CookieName())
+			if d := client.throttle.wait(key); d > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+				client.exit(http.StatusTooManyRequests)
+			}
+
+			if !protection.Check(r.FormValue(vars.URLQueryPassword)) {
+				client.throttle.fail(key)
+				http.Redirect(w, r, post.Permalink+"?"+vars.URLQueryError+"=1", http.StatusSeeOther)
+				return
+			}
+			client.throttle.reset(key)
+
+			http.SetCookie(w, &http.Cookie{
+				Name:     protection.CookieName(),
+				Value:    protection.Token(client.secret),
+				Path:     client.data.Permalinks.Root() + "/", // 同一分组的文章共用该 cookie
+				HttpOnly: true,
+				Secure:   strings.HasPrefix(client.data.Permalinks.SiteURL(), "https://"), // 可能由反向代理处理 TLS
+				SameSite: http.SameSiteLaxMode,
+			})
+			http.Redirect(w, r, post.Permalink, http.StatusSeeOther)
+			return
+		}
+
+		ctx := web.NewContext(w, r)
+		if cookie, err := r.Cookie(protection.CookieName()); err == nil && protection.Verify(client.secret, cookie.Value) {
+			client.renderPost(ctx, index)
+			return
+		}
+
+		// 只传递表单需要的内容，避免主题输出文章正文
+		p := client.page(ctx, vars.PageProtected)
+		p.Title = post.HTMLTitle
+		p.Canonical = client.data.Permalinks.AbsURL(post.Permalink)
+		p.Protected = &page.Protected{
+			Title:     post.Title,
+			Permalink: post.Permalink,
+			Error:     r.FormValue(vars.URLQueryError) != "",
+		}
+		p.Render(vars.PageProtected)
+	}
+}
+
+// 独立页面
+// /pages/about.html
+func (client *Client) getSinglePage(page *data.SinglePage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p := client.page(web.NewContext(w, r), vars.PageSingle)
+
+		p.Single = page
+		p.Keywords = page.Keywords
+		p.Description = page.Summary
+		p.Title = page.HTMLTitle
+		p.Canonical = client.data.Permalinks.AbsURL(page.Permalink)
+
+		// 各语言的独立页面以 slug 关联
+		p.Translate(client.data, func(d *data.Data) string {
+			for _, sp := range d.SinglePages {
+				if sp.Slug == page.Slug {
+					return sp.Permalink
+				}
+			}
+			return ""
+		})
+
+		p.Render(page.Template)
+	}
+}
+
 // 首页及文章列表页
 // /
 // /index.html?page=2
 func (client *Client) getPosts(w http.ResponseWriter, r *http.Request) {
 	ctx := web.NewContext(w, r)
-	page := client.queryInt(ctx, vars.URLQueryPage, 1)
+	page := client.pageNumber(ctx)
 
 	if page < 1 {
-		ctx.Exit(http.StatusNotFound) // 页码为负数的表示不存在，跳转到 404 页面
+		client.exit(http.StatusNotFound) // 页码为负数的表示不存在，跳转到 404 页面
 		return
 	}
 
@@ -148,51 +300,43 @@ func (client *Client) getPosts(w http.ResponseWriter, r *http.Request) {
 	p.Title = pp.Title
 	p.Keywords = pp.Keywords
 	p.Description = pp.Description
-	p.Canonical = web.URL(vars.PostsURL(page))
+	p.Canonical = client.data.Permalinks.AbsURL(client.data.Permalinks.PostsURL(page))
 
-	start, end, ok := client.getPostsRange(len(client.data.Posts), page, w, r)
+	start, end, ok := client.getPostsRange(len(client.data.Posts), page)
 	if !ok {
 		return
 	}
 	p.Posts = client.data.Posts[start:end]
 	if page > 1 {
-		p.Prev(vars.PostsURL(page-1), "")
+		p.Prev(client.data.Permalinks.PostsURL(page-1), "")
 	}
 	if end < len(client.data.Posts) {
-		p.Next(vars.PostsURL(page+1), "")
+		p.Next(client.data.Permalinks.PostsURL(page+1), "")
 	}
 
+	// 各语言的文章数量不同，分页之后的内容并不对应，只关联首页。
+	p.Translate(client.data, func(d *data.Data) string {
+		if page > 1 {
+			return ""
+		}
+		return d.Permalinks.PostsURL(1)
+	})
+
 	p.Render(vars.PagePosts)
 }
 
 // 标签详细页
 // /tags/tag1.html?page=2
-func (client *Client) getTag(w http.ResponseWriter, r *http.Request) {
-	ctx := web.NewContext(w, r)
-	slug, err := ctx.ParamString("slug")
-	if err != nil {
-		logs.Error(err)
-		client.getRaw(w, r)
-		return
-	}
-
-	var tag *data.Tag
-	for _, t := range client.data.Tags {
-		if t.Slug == slug {
-			tag = t
-			break
-		}
-	}
-
-	if tag == nil {
-		logs.Debugf("查找的标签 %s 不存在", slug)
-		client.getRaw(w, r) // 标签不存在，则查找该文件是否存在于 raws 目录下。
-		return
+func (client *Client) getTag(tag *data.Tag) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client.renderTag(web.NewContext(w, r), tag)
 	}
+}
 
-	page := client.queryInt(ctx, vars.URLQueryPage, 1)
+func (client *Client) renderTag(ctx *context.Context, tag *data.Tag) {
+	page := client.pageNumber(ctx)
 	if page < 1 {
-		ctx.Exit(http.StatusNotFound) // 页码为负数的表示不存在，跳转到 404 页面
+		client.exit(http.StatusNotFound) // 页码为负数的表示不存在，跳转到 404 页面
 	}
 
 	p := client.page(ctx, vars.PageTag)
@@ -200,23 +344,87 @@ func (client *Client) getTag(w http.ResponseWriter, r *http.Request) {
 	p.Title = tag.HTMLTitle
 	p.Keywords = tag.Keywords
 	p.Description = tag.Content
-	p.Canonical = web.URL(vars.TagURL(slug, page))
+	p.Canonical = client.data.Permalinks.AbsURL(client.data.Permalinks.TagURL(tag.Slug, page))
 
-	start, end, ok := client.getPostsRange(len(tag.Posts), page, w, r)
+	start, end, ok := client.getPostsRange(len(tag.Posts), page)
 	if !ok {
 		return
 	}
 	p.Posts = tag.Posts[start:end]
 	if page > 1 {
-		p.Prev(vars.TagURL(slug, page-1), "")
+		p.Prev(client.data.Permalinks.TagURL(tag.Slug, page-1), "")
 	}
 	if end < len(tag.Posts) {
-		p.Next(vars.TagURL(slug, page+1), "")
+		p.Next(client.data.Permalinks.TagURL(tag.Slug, page+1), "")
 	}
 
+	p.Translate(client.data, func(d *data.Data) string {
+		if t := findTag(d, tag.Slug); t != nil && page == 1 {
+			return t.Permalink
+		}
+		return ""
+	})
+
 	p.Render(vars.PageTag)
 }
 
+// 作者详细页
+// /authors/caixw.html?page=2
+func (client *Client) getAuthor(author *data.Author) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client.renderAuthor(web.NewContext(w, r), author)
+	}
+}
+
+func (client *Client) renderAuthor(ctx *context.Context, author *data.Author) {
+	page := client.pageNumber(ctx)
+	if page < 1 {
+		client.exit(http.StatusNotFound) // 页码为负数的表示不存在，跳转到 404 页面
+	}
+
+	p := client.page(ctx, vars.PageAuthor)
+	p.Author = author
+	p.Title = author.HTMLTitle
+	p.Description = author.Bio
+	p.Canonical = client.data.Permalinks.AbsURL(client.data.Permalinks.AuthorURL(author.ID, page))
+
+	start, end, ok := client.getPostsRange(len(author.Posts), page)
+	if !ok {
+		return
+	}
+	p.Posts = author.Posts[start:end]
+	if page > 1 {
+		p.Prev(client.data.Permalinks.AuthorURL(author.ID, page-1), "")
+	}
+	if end < len(author.Posts) {
+		p.Next(client.data.Permalinks.AuthorURL(author.ID, page+1), "")
+	}
+
+	p.Translate(client.data, func(d *data.Data) string {
+		if a := findAuthor(d, author.ID); a != nil && page == 1 {
+			return a.Permalink
+		}
+		return ""
+	})
+
+	p.Render(vars.PageAuthor)
+}
+
+// 作者列表页
+// /authors.html
+func (client *Client) getAuthors(w http.ResponseWriter, r *http.Request) {
+	ctx := web.NewContext(w, r)
+	p := client.page(ctx, vars.PageAuthors)
+	pp := client.data.Pages[vars.PageAuthors]
+	p.Title = pp.Title
+	p.Keywords = pp.Keywords
+	p.Description = pp.Description
+	p.Canonical = client.data.Permalinks.AbsURL(client.data.Permalinks.AuthorsURL())
+	p.Translate(client.data, func(d *data.Data) string { return d.Permalinks.AuthorsURL() })
+
+	p.Render(vars.PageAuthors)
+}
+
 // 友情链接页
 // /links.html
 func (client *Client) getLinks(w http.ResponseWriter, r *http.Request) {
@@ -226,7 +434,8 @@ func (client *Client) getLinks(w http.ResponseWriter, r *http.Request) {
 	p.Title = pp.Title
 	p.Keywords = pp.Keywords
 	p.Description = pp.Description
-	p.Canonical = web.URL(vars.LinksURL())
+	p.Canonical = client.data.Permalinks.AbsURL(client.data.Permalinks.LinksURL())
+	p.Translate(client.data, func(d *data.Data) string { return d.Permalinks.LinksURL() })
 
 	p.Render(vars.PageLinks)
 }
@@ -240,7 +449,8 @@ func (client *Client) getTags(w http.ResponseWriter, r *http.Request) {
 	p.Title = pp.Title
 	p.Keywords = pp.Keywords
 	p.Description = pp.Description
-	p.Canonical = web.URL(vars.TagsURL())
+	p.Canonical = client.data.Permalinks.AbsURL(client.data.Permalinks.TagsURL())
+	p.Translate(client.data, func(d *data.Data) string { return d.Permalinks.TagsURL() })
 
 	p.Render(vars.PageTags)
 }
@@ -254,20 +464,114 @@ func (client *Client) getArchives(w http.ResponseWriter, r *http.Request) {
 	p.Title = pp.Title
 	p.Keywords = pp.Keywords
 	p.Description = pp.Description
-	p.Canonical = web.URL(vars.ArchivesURL())
+	p.Canonical = client.data.Permalinks.AbsURL(client.data.Permalinks.ArchivesURL())
+	p.Translate(client.data, func(d *data.Data) string { return d.Permalinks.ArchivesURL() })
 	p.Archives = client.data.Archives
 
 	p.Render(vars.PageArchives)
 }
 
+// 按年或是按月的存档页
+// /archives/2018.html?page=2
+// /archives/2018/01.html?page=2
+func (client *Client) getArchive(period *data.ArchivePeriod) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client.renderArchive(web.NewContext(w, r), period)
+	}
+}
+
+func (client *Client) renderArchive(ctx *context.Context, period *data.ArchivePeriod) {
+	page := client.pageNumber(ctx)
+	if page < 1 {
+		client.exit(http.StatusNotFound) // 页码为负数的表示不存在，跳转到 404 页面
+	}
+
+	url := func(page int) string {
+		if period.Month == 0 {
+			return client.data.Permalinks.ArchiveYearURL(period.Year, page)
+		}
+		return client.data.Permalinks.ArchiveMonthURL(period.Year, period.Month, page)
+	}
+
+	p := client.page(ctx, vars.PageArchive)
+	pp := client.data.Pages[vars.PageArchive]
+	p.Period = period
+	p.Title = period.HTMLTitle
+	p.Keywords = pp.Keywords
+	p.Description = pp.Description
+	p.Canonical = client.data.Permalinks.AbsURL(url(page))
+
+	start, end, ok := client.getPostsRange(len(period.Posts), page)
+	if !ok {
+		return
+	}
+	p.Posts = period.Posts[start:end]
+	if page > 1 {
+		p.Prev(url(page-1), "")
+	}
+	if end < len(period.Posts) {
+		p.Next(url(page+1), "")
+	}
+
+	p.Translate(client.data, func(d *data.Data) string {
+		if a := findPeriod(d, period.Year, period.Month); a != nil && page == 1 {
+			return a.Permalink
+		}
+		return ""
+	})
+
+	p.Render(vars.PageArchive)
+}
+
+// 查找 d 中的标签或是专题，不存在时返回空值
+func findTag(d *data.Data, slug string) *data.Tag {
+	for _, tags := range [][]*data.Tag{d.Tags, d.Series} {
+		for _, tag := range tags {
+			if tag.Slug == slug {
+				return tag
+			}
+		}
+	}
+	return nil
+}
+
+// 查找 d 中的存档页，month 为 0 表示按年的存档页，不存在时返回空值
+func findPeriod(d *data.Data, year, month int) *data.ArchivePeriod {
+	for _, y := range d.Calendar {
+		if y.Year != year {
+			continue
+		}
+
+		if month == 0 {
+			return y
+		}
+
+		for _, m := range y.Months {
+			if m.Month == month {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// 查找 d 中的作者，不存在时返回空值
+func findAuthor(d *data.Data, id string) *data.Author {
+	for _, author := range d.Authors {
+		if author.ID == id {
+			return author
+		}
+	}
+	return nil
+}
+
 // 确认当前文章列表页选择范围。
-func (client *Client) getPostsRange(postsSize, page int, w http.ResponseWriter, r *http.Request) (start, end int, ok bool) {
-	ctx := web.NewContext(w, r)
+func (client *Client) getPostsRange(postsSize, page int) (start, end int, ok bool) {
 	size := client.data.PageSize
 	start = size * (page - 1) // 系统从零开始计数
 	if start > postsSize {
 		logs.Debugf("请求页码为[%d]，实际文章数量为[%d]\n", page, postsSize)
-		ctx.Exit(http.StatusNotFound) // 页码超出范围，不存在
+		client.exit(http.StatusNotFound) // 页码超出范围，不存在
 		return 0, 0, false
 	}
 
@@ -279,6 +583,27 @@ func (client *Client) getPostsRange(postsSize, page int, w http.ResponseWriter,
 	return start, end, true
 }
 
+// 获取当前请求的页码，根据 permalinks.page 的格式，从路由参数或是查询参数中获取，
+// 不存在时返回 1。
+func (client *Client) pageNumber(ctx *context.Context) int {
+	key := client.data.Permalinks.PageQuery()
+	if key != "" {
+		return client.queryInt(ctx, key, 1)
+	}
+
+	params := mux.Params(ctx.Request)
+	if !params.Exists(vars.URLQueryPage) {
+		return 1
+	}
+
+	page, err := params.Int(vars.URLQueryPage)
+	if err != nil {
+		logs.Error(err)
+		client.exit(http.StatusBadRequest)
+	}
+	return int(page)
+}
+
 // 获取查询参数 key 的值，并将其转换成 Int 类型，若该值不存在返回 def 作为其默认值，
 // 若是类型不正确，则返回一个 false，并向客户端输出一个 400 错误。
 func (client *Client) queryInt(ctx *context.Context, key string, def int) int {
@@ -287,7 +612,7 @@ func (client *Client) queryInt(ctx *context.Context, key string, def int) int {
 
 	if q.HasErrors() {
 		logs.Error(q.Errors()[key])
-		ctx.Exit(http.StatusBadRequest)
+		client.exit(http.StatusBadRequest)
 	}
 
 	return v
@@ -6,10 +6,16 @@ package client
 
 import (
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/issue9/assert"
 	"github.com/issue9/assert/rest"
 	"github.com/issue9/web"
+
+	"github.com/caixw/gitype/data"
 )
 
 func TestPost(t *testing.T) {
@@ -66,6 +72,111 @@ func TestPost(t *testing.T) {
 		Status(http.StatusOK)
 }
 
+func TestProtectedPost(t *testing.T) {
+	h, err := web.Handler()
+	if err != nil {
+		panic(err)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		panic(err)
+	}
+	s := rest.NewServer(t, h, &http.Client{Jar: jar})
+
+	// 未验证，输出密码输入页
+	s.NewRequest(http.MethodGet, "/posts/protected.html").
+		Do().
+		Header("Cache-Control", "private, no-store").
+		Header("Etag", "").
+		StringBody("\n<h1>protected</h1>\n").
+		Status(http.StatusOK)
+
+	// 密码错误，跳转回密码输入页
+	s.NewRequest(http.MethodPost, "/posts/protected.html").
+		Header("Content-Type", "application/x-www-form-urlencoded").
+		Body([]byte("password=456")).
+		Do().
+		StringBody("\n<h1>protected</h1>\n").
+		Status(http.StatusOK)
+
+	// 密码正确，跳转回文章页
+	s.NewRequest(http.MethodPost, "/posts/protected.html").
+		Header("Content-Type", "application/x-www-form-urlencoded").
+		Body([]byte("password=123")).
+		Do().
+		BodyNotNil().
+		NotHeader("Cache-Control", "").
+		Status(http.StatusOK)
+
+	// 已经验证，内容的检测在 TestProtectedPost_content 中
+	s.NewRequest(http.MethodGet, "/posts/protected.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	// 失败次数过多
+	group := protectedPost(t).Protection.CookieName()
+	defer client.throttle.reset(throttleKey(&http.Request{RemoteAddr: "127.0.0.1:0"}, group))
+	for i := 0; i < passwordFailures; i++ {
+		s.NewRequest(http.MethodPost, "/posts/protected.html").
+			Header("Content-Type", "application/x-www-form-urlencoded").
+			Body([]byte("password=456")).
+			Do().
+			Status(http.StatusOK)
+	}
+	s.NewRequest(http.MethodPost, "/posts/protected.html").
+		Header("Content-Type", "application/x-www-form-urlencoded").
+		Body([]byte("password=123")).
+		Do().
+		NotHeader("Retry-After", "").
+		Status(http.StatusTooManyRequests)
+}
+
+// 测试数据中唯一的受保护文章
+func protectedPost(t *testing.T) *data.Post {
+	for _, post := range client.data.Posts {
+		if post.Slug == "protected" {
+			return post
+		}
+	}
+	t.Fatal("测试数据中不存在 protected")
+	return nil
+}
+
+// 受保护文章的内容只在验证通过之后才输出，
+// 列表页以及其它文章的相关文章中都不能包含。
+func TestProtectedPost_content(t *testing.T) {
+	a := assert.New(t)
+	h, err := web.Handler()
+	a.NotError(err)
+
+	get := func(url string, cookies ...*http.Cookie) string {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		for _, c := range cookies {
+			r.AddCookie(c)
+		}
+		h.ServeHTTP(w, r)
+		a.Equal(w.Code, http.StatusOK, url)
+		return w.Body.String()
+	}
+
+	const notes = "internal notes"
+
+	body := get("/")
+	a.True(strings.Contains(body, "a1")) // 公开文章的内容
+	a.False(strings.Contains(body, notes))
+
+	a.False(strings.Contains(get("/tags/default2.html"), notes))
+	a.False(strings.Contains(get("/posts/post1.html"), notes)) // 相关文章
+	a.False(strings.Contains(get("/posts/protected.html"), notes))
+
+	// 验证通过
+	protection := protectedPost(t).Protection
+	cookie := &http.Cookie{Name: protection.CookieName(), Value: protection.Token(client.secret)}
+	a.True(strings.Contains(get("/posts/protected.html", cookie), notes))
+}
+
 func TestRoutes(t *testing.T) {
 	h, err := web.Handler()
 	if err != nil {
@@ -119,14 +230,149 @@ func TestRoutes(t *testing.T) {
 		BodyNotNil().
 		Status(http.StatusOK)
 
+	// tags/... 子标签
+	s.NewRequest(http.MethodGet, "/tags/child.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
 	// tags/...
+	// 专题与标签使用相同的地址格式
+	s.NewRequest(http.MethodGet, "/tags/series1.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
 	s.NewRequest(http.MethodGet, "/tags/not-exists.html").
 		Do().
 		Status(http.StatusNotFound)
 
+	// pages/...
+	s.NewRequest(http.MethodGet, "/pages/privacy.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	// archives/...
+	s.NewRequest(http.MethodGet, "/archives/2016.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	s.NewRequest(http.MethodGet, "/archives/2016/01.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	s.NewRequest(http.MethodGet, "/archives/2017.html").
+		Do().
+		Status(http.StatusNotFound)
+
+	// authors.html
+	s.NewRequest(http.MethodGet, "/authors.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	// authors/...
+	s.NewRequest(http.MethodGet, "/authors/caixw.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	s.NewRequest(http.MethodGet, "/authors/caixw.html?page=10000").
+		Do().
+		Status(http.StatusNotFound)
+
+	// authors/caixw.xml
+	s.NewRequest(http.MethodGet, "/authors/caixw.xml").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
 		// tags/...不存在并跳转到 getRaws
 	s.NewRequest(http.MethodGet, "/tags/raws.html").
 		Do().
 		StringBody("raws.html\n").
 		Status(http.StatusOK)
 }
+
+func TestLanguages(t *testing.T) {
+	h, err := web.Handler()
+	if err != nil {
+		panic(err)
+	}
+	s := rest.NewServer(t, h, nil)
+
+	s.NewRequest(http.MethodGet, "/en/").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	// 跳转到 /en/
+	s.NewRequest(http.MethodGet, "/en").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	s.NewRequest(http.MethodGet, "/en/posts/post1.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	s.NewRequest(http.MethodGet, "/en/tags/default1.html").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	s.NewRequest(http.MethodGet, "/en/atom.xml").
+		Do().
+		BodyNotNil().
+		Status(http.StatusOK)
+
+	// 只有 post1 有英文版本
+	s.NewRequest(http.MethodGet, "/en/posts/folder/post2.html").
+		Do().
+		Status(http.StatusNotFound)
+}
+
+func TestRedirects(t *testing.T) {
+	h, err := web.Handler()
+	if err != nil {
+		panic(err)
+	}
+
+	// 不自动跳转，才能检测状态码和 Location 报头
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	s := rest.NewServer(t, h, client)
+
+	// redirects.yaml
+	s.NewRequest(http.MethodGet, "/about.html").
+		Do().
+		Header("Location", "/posts/post1.html").
+		Status(http.StatusMovedPermanently)
+
+	s.NewRequest(http.MethodGet, "/old/links.html").
+		Do().
+		Header("Location", "/links.html").
+		Status(http.StatusFound)
+
+	s.NewRequest(http.MethodGet, "/deleted.html").
+		Do().
+		Status(http.StatusGone)
+
+	// 文章的 aliases
+	s.NewRequest(http.MethodGet, "/posts/old-post2.html").
+		Do().
+		Header("Location", "/posts/folder/post2.html").
+		Status(http.StatusMovedPermanently)
+
+	s.NewRequest(http.MethodGet, "/2016/post2.html").
+		Do().
+		Header("Location", "/posts/folder/post2.html").
+		Status(http.StatusMovedPermanently)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/issue9/web"
+	ts "golang.org/x/text/search"
 
 	"github.com/caixw/gitype/data"
 	"github.com/caixw/gitype/helper"
@@ -22,13 +23,13 @@ func (client *Client) getSearch(w http.ResponseWriter, r *http.Request) {
 
 	q := r.FormValue(vars.URLQuerySearch)
 	if len(q) == 0 {
-		http.Redirect(w, r, vars.PostsURL(1), http.StatusPermanentRedirect)
+		http.Redirect(w, r, client.data.Permalinks.PostsURL(1), http.StatusPermanentRedirect)
 		return
 	}
 
-	page := client.queryInt(ctx, vars.URLQueryPage, 1)
+	page := client.pageNumber(ctx)
 	if page < 1 {
-		ctx.Exit(http.StatusNotFound) // 页码为负数的表示不存在，跳转到 404 页面
+		client.exit(http.StatusNotFound) // 页码为负数的表示不存在，跳转到 404 页面
 	}
 
 	pp := client.data.Pages[vars.PageSearch]
@@ -36,21 +37,28 @@ func (client *Client) getSearch(w http.ResponseWriter, r *http.Request) {
 	p.Keywords = helper.ReplaceContent(pp.Keywords, q)
 	p.Description = helper.ReplaceContent(pp.Description, q)
 	p.Q = q
-	p.Canonical = web.URL(vars.SearchURL(p.Q, page))
+	p.Canonical = client.data.Permalinks.AbsURL(client.data.Permalinks.SearchURL(p.Q, page))
 
-	posts := search(q, client.data) // 获取所有的搜索结果
-	start, end, ok := client.getPostsRange(len(posts), page, w, r)
+	posts := data.PublicPosts(search(q, client.data)) // 获取所有的搜索结果，不包含受保护的文章
+	start, end, ok := client.getPostsRange(len(posts), page)
 	if !ok {
 		return
 	}
 	p.Posts = posts[start:end]
 	if page > 1 {
-		p.Prev(vars.SearchURL(q, page-1), "")
+		p.Prev(client.data.Permalinks.SearchURL(q, page-1), "")
 	}
 	if end < len(posts) {
-		p.Next(vars.SearchURL(q, page+1), "")
+		p.Next(client.data.Permalinks.SearchURL(q, page+1), "")
 	}
 
+	p.Translate(client.data, func(d *data.Data) string {
+		if page > 1 {
+			return ""
+		}
+		return d.Permalinks.SearchURL(q, 1)
+	})
+
 	p.Render(vars.PageSearch)
 }
 
@@ -83,7 +91,7 @@ func searchSeries(q string, d *data.Data) []*data.Post {
 	posts := make([]*data.Post, 0, len(d.Posts))
 
 	for _, tag := range d.Series {
-		if containes(d, tag.Title, q) {
+		if containes(d.Matcher(d.LanguageTag), tag.Title, q) {
 			posts = append(posts, tag.Posts...)
 		}
 	}
@@ -96,7 +104,7 @@ func searchTag(q string, d *data.Data) []*data.Post {
 	posts := make([]*data.Post, 0, len(d.Posts))
 
 	for _, tag := range d.Tags {
-		if containes(d, tag.Title, q) {
+		if containes(d.Matcher(d.LanguageTag), tag.Title, q) {
 			posts = append(posts, tag.Posts...)
 		}
 	}
@@ -109,7 +117,7 @@ func searchTitle(q string, d *data.Data) []*data.Post {
 	posts := make([]*data.Post, 0, len(d.Posts))
 
 	for _, post := range d.Posts {
-		if containes(d, post.Title, q) {
+		if containes(d.Matcher(post.LanguageTag), post.Title, q) {
 			posts = append(posts, post)
 		}
 	}
@@ -122,9 +130,10 @@ func searchDefault(q string, d *data.Data) []*data.Post {
 	posts := make([]*data.Post, 0, len(d.Posts))
 
 	for _, post := range d.Posts {
-		if containes(d, post.Title, q) ||
-			containes(d, post.Content, q) ||
-			containes(d, post.Summary, q) {
+		m := d.Matcher(post.LanguageTag)
+		if containes(m, post.Title, q) ||
+			containes(m, post.Content, q) ||
+			containes(m, post.Summary, q) {
 			posts = append(posts, post)
 		}
 	}
@@ -132,7 +141,8 @@ func searchDefault(q string, d *data.Data) []*data.Post {
 	return posts
 }
 
-func containes(d *data.Data, text, key string) bool {
-	s1, _ := d.Matcher.IndexString(text, key)
+// 以 m 的语言规则判断 text 中是否包含 key
+func containes(m *ts.Matcher, text, key string) bool {
+	s1, _ := m.IndexString(text, key)
 	return s1 >= 0
 }
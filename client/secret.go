@@ -0,0 +1,56 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caixw/gitype/path"
+	"github.com/caixw/gitype/vars"
+)
+
+// 随机生成的密钥长度
+const secretSize = 32
+
+// 加载 conf 目录下的密钥，不存在时随机生成一个并保存。
+//
+// 该密钥用于签名受保护文章的 cookie，只保存在 conf 目录下，
+// 不会出现在公开的数据目录中，删除该文件可使所有的 cookie 失效。
+func loadSecret(path *path.Path) ([]byte, error) {
+	file := filepath.Join(path.ConfDir, vars.SecretFilename)
+
+	data, err := ioutil.ReadFile(file)
+	if err == nil {
+		secret, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, err
+		}
+		if len(secret) == 0 {
+			return nil, errors.New("密钥不能为空")
+		}
+		return secret, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	secret := make([]byte, secretSize)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	if err = ioutil.WriteFile(file, []byte(hex.EncodeToString(secret)), 0600); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/path"
+	"github.com/caixw/gitype/vars"
+)
+
+func TestLoadSecret(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gitype")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	p := path.New(dir)
+	a.NotError(os.Mkdir(p.ConfDir, os.ModePerm))
+
+	// 不存在时生成
+	s1, err := loadSecret(p)
+	a.NotError(err).Equal(len(s1), secretSize)
+	a.FileExists(filepath.Join(p.ConfDir, vars.SecretFilename))
+
+	// 存在时直接加载
+	s2, err := loadSecret(p)
+	a.NotError(err).Equal(s1, s2)
+
+	// 格式错误
+	a.NotError(ioutil.WriteFile(filepath.Join(p.ConfDir, vars.SecretFilename), []byte("xyz"), 0600))
+	s3, err := loadSecret(p)
+	a.Error(err).Nil(s3)
+}
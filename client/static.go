@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/caixw/gitype/path"
 	"github.com/caixw/gitype/vars"
 	"github.com/issue9/logs"
 	"github.com/issue9/mux"
@@ -24,7 +26,7 @@ func (client *Client) getAsset(w http.ResponseWriter, r *http.Request) {
 
 	// 不展示模板文件，查看 raws 中是否有同名文件
 	name := filepath.Base(r.URL.Path)
-	if name == vars.PostMetaFilename || name == vars.PostContentFilename {
+	if path.IsPostFile(name) { // 包括各个语言版本
 		client.getRaw(w, r)
 		return
 	}
@@ -68,19 +70,35 @@ func (client *Client) getTheme(w http.ResponseWriter, r *http.Request) {
 	client.serveFile(ctx, filename)
 }
 
+// 程序生成的图片
+// /images/...
+func (client *Client) getImage(w http.ResponseWriter, r *http.Request) {
+	ctx := web.NewContext(w, r)
+
+	path, err := mux.Params(r).String("path")
+	if err != nil {
+		logs.Error(err)
+		client.getRaw(w, r)
+		return
+	}
+
+	client.serveFile(ctx, client.path.ImagePath(path))
+}
+
 // /...
 func (client *Client) getRaw(w http.ResponseWriter, r *http.Request) {
 	ctx := web.NewContext(w, r)
-	if ctx.Request.URL.Path == "/" {
+	prefix := client.data.Permalinks.PostsURL(1) // 网站的根目录，部署在子目录下时为 /blog/ 等
+	if ctx.Request.URL.Path == prefix {
 		client.getPosts(w, r)
 		return
 	}
 
-	if !utils.FileExists(filepath.Join(client.path.RawsDir, r.URL.Path)) {
-		ctx.Exit(http.StatusNotFound)
+	name := strings.TrimPrefix(r.URL.Path, prefix)
+	if !utils.FileExists(filepath.Join(client.path.RawsDir, name)) {
+		client.exit(http.StatusNotFound)
 	}
 
-	prefix := "/"
 	root := http.Dir(client.path.RawsDir)
 	http.StripPrefix(prefix, http.FileServer(root)).ServeHTTP(w, r)
 }
@@ -93,7 +111,8 @@ func (client *Client) serveFile(ctx *context.Context, filename string) {
 
 	stat, err := os.Stat(filename)
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err)
+		logs.Error(err)
+		client.RenderError(ctx.Response, http.StatusInternalServerError)
 		return
 	}
 
@@ -36,6 +36,15 @@ func TestGetAsset(t *testing.T) {
 		Do().
 		Status(http.StatusNotFound)
 
+	// 其它语言版本的内容和元数据
+	s.NewRequest(http.MethodGet, "/posts/post1/content.en.html").
+		Do().
+		Status(http.StatusNotFound)
+
+	s.NewRequest(http.MethodGet, "/posts/post1/meta.en.yaml").
+		Do().
+		Status(http.StatusNotFound)
+
 	// 跳转到 getRaws
 	s.NewRequest(http.MethodGet, "/posts/folder/post2/raws.txt").
 		Do().
@@ -56,6 +65,12 @@ func TestGetTheme(t *testing.T) {
 		StringBody("*{}\n").
 		Status(http.StatusOK)
 
+	// 代码高亮的样式表
+	s.NewRequest(http.MethodGet, "/themes/highlight.css").
+		Do().
+		Header("Content-Type", "text/css").
+		Status(http.StatusOK)
+
 	// 模板文件
 	s.NewRequest(http.MethodGet, "/themes/t1/template.html").
 		Do().
@@ -0,0 +1,111 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 受保护文章的密码验证限制：
+// 同一客户端在 passwordWindow 时间内最多只能失败 passwordFailures 次。
+const (
+	passwordFailures = 5
+	passwordWindow   = 10 * time.Minute
+
+	// 记录数超过此值时，清除已经过期的记录。
+	throttleSweepSize = 1024
+)
+
+// 按客户端限制失败的次数
+type throttle struct {
+	max    int
+	window time.Duration
+
+	locker sync.Mutex
+	items  map[string]*throttleItem
+}
+
+type throttleItem struct {
+	count   int       // 失败的次数
+	expires time.Time // 记录的过期时间
+}
+
+func newThrottle(max int, window time.Duration) *throttle {
+	return &throttle{
+		max:    max,
+		window: window,
+		items:  make(map[string]*throttleItem, 10),
+	}
+}
+
+// 获取 key 需要等待的时间，返回 0 表示可以继续尝试。
+func (t *throttle) wait(key string) time.Duration {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+
+	item, found := t.items[key]
+	if !found {
+		return 0
+	}
+
+	d := time.Until(item.expires)
+	if d <= 0 {
+		delete(t.items, key)
+		return 0
+	}
+
+	if item.count < t.max {
+		return 0
+	}
+	return d
+}
+
+// 记录一次失败
+func (t *throttle) fail(key string) {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+
+	now := time.Now()
+
+	if len(t.items) >= throttleSweepSize {
+		for k, item := range t.items {
+			if !item.expires.After(now) {
+				delete(t.items, k)
+			}
+		}
+	}
+
+	item, found := t.items[key]
+	if !found || !item.expires.After(now) {
+		item = &throttleItem{expires: now.Add(t.window)}
+		t.items[key] = item
+	}
+	item.count++
+}
+
+// 清除 key 的记录
+func (t *throttle) reset(key string) {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+
+	delete(t.items, key)
+}
+
+// 以客户端的 IP 和文章所在的分组作为限制的依据，
+// 对某一分组的尝试不会影响其它分组的访问。
+//
+// 通过反向代理部署时，所有请求的 IP 都是代理服务器的地址，
+// 此时所有客户端在同一分组下共用同一个限制。
+// 代理服务器提供的 X-Forwarded-For 等报头可以被客户端伪造，所以不作为依据。
+func throttleKey(r *http.Request, group string) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return host + "|" + group
+}
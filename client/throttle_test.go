@@ -0,0 +1,56 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestThrottle(t *testing.T) {
+	a := assert.New(t)
+	th := newThrottle(2, 50*time.Millisecond)
+
+	a.Equal(th.wait("1"), 0)
+	th.fail("1")
+	a.Equal(th.wait("1"), 0)
+	th.fail("1")
+	a.True(th.wait("1") > 0)
+
+	// 不影响其它客户端
+	a.Equal(th.wait("2"), 0)
+
+	// 过期之后可以继续尝试
+	time.Sleep(60 * time.Millisecond)
+	a.Equal(th.wait("1"), 0)
+
+	// reset
+	th.fail("1")
+	th.fail("1")
+	a.True(th.wait("1") > 0)
+	th.reset("1")
+	a.Equal(th.wait("1"), 0)
+}
+
+func TestThrottleKey(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := http.NewRequest(http.MethodPost, "/", nil)
+	a.NotError(err)
+
+	r.RemoteAddr = "127.0.0.1:8080"
+	a.Equal(throttleKey(r, "g1"), "127.0.0.1|g1")
+	a.NotEqual(throttleKey(r, "g1"), throttleKey(r, "g2"))
+
+	r.RemoteAddr = "[::1]:8080"
+	a.Equal(throttleKey(r, "g1"), "::1|g1")
+
+	// 代理服务器的报头不作为依据
+	r.Header.Set("X-Forwarded-For", "10.0.0.1")
+	a.Equal(throttleKey(r, "g1"), "::1|g1")
+}
@@ -10,27 +10,54 @@ import (
 
 	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/vars"
 )
 
 // Archive 表示某一时间段的存档信息
 type Archive struct {
-	date  time.Time // 当前存档的一个日期值，可用于生成 Title 和排序用，具体取值方式，可自定义
-	Title string    // 当前存档的标题
-	Posts []*Post   // 当前存档的文章列表
+	date      time.Time // 当前存档的一个日期值，可用于生成 Title 和排序用，具体取值方式，可自定义
+	Title     string    // 当前存档的标题
+	Permalink string    // 对应的按年或是按月存档页的地址
+	Posts     []*Post   // 当前存档的文章列表
+}
+
+// ArchivePeriod 表示按年或是按月的存档页
+//
+// 所有的年份组成 Data.Calendar，每个年份下又包含了该年的各个月份，
+// 可用于在侧边栏等位置输出日历形式的存档。
+type ArchivePeriod struct {
+	Year      int
+	Month     int    // 月份，为 0 表示整年的存档
+	Title     string // 标题，根据 archive.yearFormat 或是 archive.monthFormat 生成
+	HTMLTitle string // 用于网页的标题
+	Permalink string // 唯一链接，指向第一页
+	Count     int    // 文章数量
+	Posts     []*Post
+	Modified  time.Time // 所有文章中最迟修改的
+
+	// 年份下的各个月份，排序方式与 archive.order 相同，月份本身则为空。
+	Months []*ArchivePeriod
+
+	// 时间上相邻的存档，年份与年份相邻，月份与月份相邻，可以跨年。
+	Prev *ArchivePeriod // 更早的存档
+	Next *ArchivePeriod // 更新的存档
 }
 
 func (d *Data) buildArchives(conf *loader.Config) error {
 	archives := make([]*Archive, 0, 10)
 
-	for _, post := range d.Posts {
+	for _, post := range archivePosts(d, conf) {
 		t := post.Created
 		var date time.Time
+		var permalink string
 
 		switch conf.Archive.Type {
 		case loader.ArchiveTypeMonth:
 			date = time.Date(t.Year(), t.Month(), 2, 0, 0, 0, 0, t.Location())
+			permalink = conf.Permalinks.ArchiveMonthURL(t.Year(), int(t.Month()), 1)
 		case loader.ArchiveTypeYear:
 			date = time.Date(t.Year(), 2, 0, 0, 0, 0, 0, t.Location())
+			permalink = conf.Permalinks.ArchiveYearURL(t.Year(), 1)
 		default:
 			return &helper.FieldError{File: d.path.MetaConfigFile, Field: "archive.type", Message: "无效的取值"}
 		}
@@ -45,9 +72,10 @@ func (d *Data) buildArchives(conf *loader.Config) error {
 		}
 		if !found {
 			archives = append(archives, &Archive{
-				date:  date,
-				Title: date.Format(conf.Archive.Format),
-				Posts: []*Post{post},
+				date:      date,
+				Title:     date.Format(conf.Archive.Format),
+				Permalink: permalink,
+				Posts:     []*Post{post},
 			})
 		}
 	} // end for
@@ -61,5 +89,105 @@ func (d *Data) buildArchives(conf *loader.Config) error {
 
 	d.Archives = archives
 
+	d.buildCalendar(conf)
+
 	return nil
 }
+
+// 生成按年和按月的存档页
+func (d *Data) buildCalendar(conf *loader.Config) {
+	years := make([]*ArchivePeriod, 0, 10)
+	months := make([]*ArchivePeriod, 0, 100)
+	title := conf.Pages[vars.PageArchive].Title
+
+	newPeriod := func(t time.Time, month int) *ArchivePeriod {
+		p := &ArchivePeriod{Year: t.Year(), Month: month, Modified: conf.Uptime}
+		if month == 0 {
+			p.Title = t.Format(conf.Archive.YearFormat)
+			p.Permalink = conf.Permalinks.ArchiveYearURL(p.Year, 1)
+		} else {
+			p.Title = t.Format(conf.Archive.MonthFormat)
+			p.Permalink = conf.Permalinks.ArchiveMonthURL(p.Year, month, 1)
+		}
+		p.HTMLTitle = helper.ReplaceContent(title, p.Title)
+		return p
+	}
+
+	find := func(periods []*ArchivePeriod, year, month int) *ArchivePeriod {
+		for _, p := range periods {
+			if p.Year == year && p.Month == month {
+				return p
+			}
+		}
+		return nil
+	}
+
+	for _, post := range archivePosts(d, conf) {
+		t := post.Created
+
+		year := find(years, t.Year(), 0)
+		if year == nil {
+			year = newPeriod(t, 0)
+			years = append(years, year)
+		}
+
+		month := find(year.Months, t.Year(), int(t.Month()))
+		if month == nil {
+			month = newPeriod(t, int(t.Month()))
+			year.Months = append(year.Months, month)
+			months = append(months, month)
+		}
+
+		for _, p := range []*ArchivePeriod{year, month} {
+			p.Posts = append(p.Posts, post)
+			p.Count++
+			if p.Modified.Before(post.Modified) {
+				p.Modified = post.Modified
+			}
+		}
+	}
+
+	linkPeriods(years)
+	linkPeriods(months)
+
+	// 与 d.Archives 的排序方式保持一致
+	desc := conf.Archive.Order == loader.ArchiveOrderDesc
+	sortPeriods(years, desc)
+	for _, year := range years {
+		sortPeriods(year.Months, desc)
+	}
+
+	d.Calendar = years
+}
+
+// 根据 archive.protected 决定存档中是否包含受保护的文章
+func archivePosts(d *Data, conf *loader.Config) []*Post {
+	if conf.Archive.Protected {
+		return d.Posts
+	}
+	return PublicPosts(d.Posts)
+}
+
+// 按时间从早到晚关联各个存档的 Prev 和 Next
+func linkPeriods(periods []*ArchivePeriod) {
+	sortPeriods(periods, false)
+	for index, p := range periods {
+		if index > 0 {
+			p.Prev = periods[index-1]
+		}
+		if index+1 < len(periods) {
+			p.Next = periods[index+1]
+		}
+	}
+}
+
+func sortPeriods(periods []*ArchivePeriod, desc bool) {
+	sort.SliceStable(periods, func(i, j int) bool {
+		vi := periods[i].Year*100 + periods[i].Month
+		vj := periods[j].Year*100 + periods[j].Month
+		if desc {
+			return vi > vj
+		}
+		return vi < vj
+	})
+}
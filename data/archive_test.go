@@ -0,0 +1,49 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+func TestData_buildArchives(t *testing.T) {
+	a := assert.New(t)
+
+	conf, err := loader.LoadConfig(testdataPath, "")
+	a.NotError(err).NotNil(conf)
+
+	p1 := &Post{Created: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)}
+	p2 := &Post{Created: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p3 := &Post{Created: time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC)}
+	d := &Data{path: testdataPath, Posts: []*Post{p1, p2, p3}}
+	a.NotError(d.buildArchives(conf))
+
+	a.Equal(len(d.Archives), 2)
+	a.Equal(d.Archives[0].Permalink, "/archives/2018.html")
+
+	// 默认按时间倒序
+	a.Equal(len(d.Calendar), 2)
+	y2018 := d.Calendar[0]
+	a.Equal(y2018.Year, 2018).
+		Equal(y2018.Month, 0).
+		Equal(y2018.Count, 2).
+		Equal(y2018.Posts, []*Post{p1, p2}).
+		Equal(y2018.Permalink, "/archives/2018.html")
+	a.Equal(len(y2018.Months), 2)
+	a.Equal(y2018.Months[0].Month, 2).
+		Equal(y2018.Months[0].Permalink, "/archives/2018/02.html").
+		Equal(y2018.Months[1].Month, 1)
+
+	// 前后关联
+	y2017 := d.Calendar[1]
+	a.Equal(y2018.Prev, y2017).Nil(y2018.Next)
+	a.Equal(y2018.Months[1].Prev, y2017.Months[0]) // 跨年
+	a.Equal(y2017.Months[0].Next, y2018.Months[1])
+}
@@ -5,11 +5,11 @@
 package data
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/data/xmlwriter"
-	"github.com/issue9/web"
 )
 
 // 用于生成一个符合 atom 规范的 XML 文本。
@@ -18,15 +18,31 @@ func (d *Data) buildAtom(conf *loader.Config) error {
 		return nil
 	}
 
+	bs, err := d.atom(conf, conf.Title, conf.Subtitle, conf.Permalinks.SiteURL(), PublicPosts(d.Posts))
+	if err != nil {
+		return err
+	}
+	d.Atom = &Feed{
+		Title:   conf.Atom.Title,
+		URL:     conf.Atom.URL,
+		Type:    conf.Atom.Type,
+		Content: bs,
+	}
+
+	return nil
+}
+
+// 生成包含 posts 的 atom 内容，link 为对应页面的完整地址，同时也作为 atom 的 id。
+func (d *Data) atom(conf *loader.Config, title, subtitle, link string, posts []*Post) ([]byte, error) {
 	w := xmlwriter.New()
 
 	w.WriteStartElement("feed", map[string]string{
 		"xmlns":            "http://www.w3.org/2005/Atom",
 		"xmlns:opensearch": "http://a9.com/-/spec/opensearch/1.1/",
 	})
-	w.WriteElement("id", web.URL(""), nil)
+	w.WriteElement("id", link, nil)
 	w.WriteCloseElement("link", map[string]string{
-		"href": web.URL(""),
+		"href": link,
 	})
 
 	if conf.Opensearch != nil {
@@ -34,45 +50,52 @@ func (d *Data) buildAtom(conf *loader.Config) error {
 		w.WriteCloseElement("link", map[string]string{
 			"rel":   "search",
 			"type":  o.Type,
-			"href":  web.URL(o.URL),
+			"href":  conf.Permalinks.AbsURL(o.URL),
 			"title": o.Title,
 		})
 	}
 
-	w.WriteElement("title", conf.Title, nil)
-	w.WriteElement("subtitle", conf.Subtitle, nil)
+	w.WriteElement("title", title, nil)
+	w.WriteElement("subtitle", subtitle, nil)
 	w.WriteElement("update", d.Created.Format(time.RFC3339), nil)
 
-	addPostsToAtom(w, d)
+	addPostsToAtom(w, d, posts)
 
 	w.WriteEndElement("feed")
 
-	bs, err := w.Bytes()
-	if err != nil {
-		return err
-	}
-	d.Atom = &Feed{
-		Title:   conf.Atom.Title,
-		URL:     conf.Atom.URL,
-		Type:    conf.Atom.Type,
-		Content: bs,
-	}
-
-	return nil
+	return w.Bytes()
 }
 
-func addPostsToAtom(w *xmlwriter.XMLWriter, d *Data) {
-	for _, p := range d.Posts {
+func addPostsToAtom(w *xmlwriter.XMLWriter, d *Data, posts []*Post) {
+	for _, p := range posts {
 		w.WriteStartElement("entry", nil)
 
 		w.WriteElement("id", p.Permalink, nil)
 
 		w.WriteCloseElement("link", map[string]string{
-			"href": web.URL(p.Permalink),
+			"href": d.Permalinks.AbsURL(p.Permalink),
 		})
 
+		if p.Enclosure != nil {
+			w.WriteCloseElement("link", map[string]string{
+				"rel":    "enclosure",
+				"href":   d.Permalinks.AbsURL(p.Enclosure.URL),
+				"type":   p.Enclosure.Type,
+				"length": strconv.FormatInt(p.Enclosure.Length, 10),
+			})
+		}
+
 		w.WriteElement("title", p.Title, nil)
 
+		for _, author := range p.Authors {
+			w.WriteStartElement("author", nil)
+			w.WriteElement("name", author.Name, nil)
+			if author.URL != "" {
+				w.WriteElement("uri", author.URL, nil)
+			}
+			w.WriteEndElement("author")
+		}
+
 		w.WriteElement("update", p.Modified.Format(time.RFC3339), nil)
 
 		w.WriteElement("summary", p.Summary, map[string]string{
@@ -0,0 +1,120 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"strings"
+	"time"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+	"github.com/caixw/gitype/vars"
+)
+
+// Author 描述作者信息
+//
+// 只有 authors.yaml 中的作者才有自己的页面和 atom，
+// config.yaml 和文章中直接指定的作者，只有 loader.Author 中的基本信息。
+type Author struct {
+	loader.Author
+
+	HTMLTitle string    // 用于网页的标题
+	Posts     []*Post   // 关联的文章
+	Modified  time.Time // 所有文章中最迟修改的
+	Permalink string    // 唯一链接，指向第一页
+	Feed      *Feed     // 作者的 atom，未配置 atom 时为空
+}
+
+func loadAuthors(path *path.Path, conf *loader.Config) ([]*Author, error) {
+	authors, err := loader.LoadAuthors(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*Author, 0, len(authors))
+	p := conf.Pages[vars.PageAuthor]
+	for _, author := range authors {
+		a := &Author{
+			Author:    *author,
+			HTMLTitle: helper.ReplaceContent(p.Title, author.Name),
+			Posts:     make([]*Post, 0, 100),
+			Modified:  conf.Uptime,
+			Permalink: conf.Permalinks.AuthorURL(author.ID, 1),
+		}
+
+		for _, link := range a.Links {
+			link.URL = conf.Permalinks.URL(link.URL)
+		}
+
+		ret = append(ret, a)
+	}
+
+	return ret, nil
+}
+
+// 关联文章与作者的相关信息
+//
+// 文章未指定 authors 时，使用文章中直接指定的 author，都未指定则使用默认作者 def。
+func attachPostAuthor(post *Post, p *loader.Post, authors []*Author, def *Author) *helper.FieldError {
+	if p.Authors == "" {
+		if p.Author != nil {
+			post.Author = &Author{Author: *p.Author}
+		} else {
+			post.Author = def
+		}
+		post.Authors = []*Author{post.Author}
+		return nil
+	}
+
+	for _, id := range strings.Split(p.Authors, ",") {
+		a := findAuthor(authors, strings.TrimSpace(id))
+		if a == nil {
+			return &helper.FieldError{File: post.meta, Message: "不存在的作者", Field: "authors"}
+		}
+
+		post.Authors = append(post.Authors, a)
+		a.Posts = append(a.Posts, post)
+		if a.Modified.Before(post.Modified) {
+			a.Modified = post.Modified
+		}
+	}
+	post.Author = post.Authors[0]
+
+	return nil
+}
+
+func findAuthor(authors []*Author, id string) *Author {
+	for _, author := range authors {
+		if author.ID == id {
+			return author
+		}
+	}
+	return nil
+}
+
+// 为每一位作者生成 atom
+func (d *Data) buildAuthorFeeds(conf *loader.Config) error {
+	if conf.Atom == nil {
+		return nil
+	}
+
+	for _, author := range d.Authors {
+		link := d.Permalinks.AbsURL(author.Permalink)
+		bs, err := d.atom(conf, author.Name+conf.TitleSeparator+conf.Title, author.Bio, link, PublicPosts(author.Posts))
+		if err != nil {
+			return err
+		}
+
+		author.Feed = &Feed{
+			Title:   author.Name + conf.TitleSeparator + conf.Atom.Title,
+			URL:     d.Permalinks.AuthorFeedURL(author.ID),
+			Type:    conf.Atom.Type,
+			Content: bs,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestLoad_authors(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.Equal(len(d.Authors), 2).Equal(d.Authors[0].ID, "caixw")
+	a.Equal(len(d.Posts[0].Authors), 2).Equal(d.Posts[0].Author, d.Authors[0])
+	a.Equal(d.Posts[1].Author.Name, "name") // 文章中直接指定的作者
+	a.Equal(d.Authors[0].Posts, []*Post{d.Posts[0]})
+	a.Equal(d.Authors[0].Feed.URL, "/authors/caixw.xml")
+}
@@ -0,0 +1,242 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/caixw/gitype/vars"
+)
+
+// Heading 表示文章目录中的一个标题
+type Heading struct {
+	ID    string     // 标题的 id 属性，可以通过 #ID 跳转到该标题
+	Level int        // 标题的级别，即 h2 为 2，h3 为 3
+	Text  string     // 标题的文本内容
+	Items []*Heading // 子标题
+}
+
+// 文章内容的 HTML 结构，解析一次之后，可以被多次处理。
+type content struct {
+	nodes []*html.Node
+}
+
+// 作为解析 HTML 片段时的上下文节点
+var contentContext = &html.Node{
+	Type:     html.ElementNode,
+	Data:     "body",
+	DataAtom: atom.Body,
+}
+
+func parseContent(text string) (*content, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(text), contentContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return &content{nodes: nodes}, nil
+}
+
+// 将内容重新转换成 HTML 字符串
+func (c *content) String() (string, error) {
+	buf := new(bytes.Buffer)
+	for _, n := range c.nodes {
+		if err := html.Render(buf, n); err != nil {
+			return "", err
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// 依次访问所有的节点，若 fn 返回 false，则不再访问其子节点。
+func (c *content) walk(fn func(*html.Node) bool) {
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if !fn(n) {
+			return
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			visit(child)
+		}
+	}
+
+	for _, n := range c.nodes {
+		visit(n)
+	}
+}
+
+// 统计字数，中日韩文字每个字计为一个字，其它文字以单词计。
+func (c *content) wordCount() int {
+	count := 0
+	c.walk(func(n *html.Node) bool {
+		switch {
+		case n.Type == html.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style):
+			return false
+		case n.Type == html.TextNode:
+			splitWords(n.Data, func(string) { count++ })
+		}
+		return true
+	})
+
+	return count
+}
+
+// 为 h2~h4 的标题添加 id 属性，并生成目录结构。
+func (c *content) buildTOC() []*Heading {
+	headings := make([]*Heading, 0, 10)
+
+	c.headings(func(n *html.Node, level int, id string) {
+		text := strings.TrimSpace(nodeText(n))
+		headings = append(headings, &Heading{ID: id, Level: level, Text: text})
+	})
+
+	return nestHeadings(headings)
+}
+
+// 依次访问 h2~h4 的标题，未指定 id 属性的，会根据其内容生成一个。
+//
+// 相同的内容多次调用，生成的 id 是相同的。
+func (c *content) headings(fn func(n *html.Node, level int, id string)) {
+	ids := make(map[string]bool, 10)
+
+	c.walk(func(n *html.Node) bool {
+		level := headingLevel(n)
+		if level == 0 {
+			return true
+		}
+
+		id := getAttr(n, "id")
+		if id == "" {
+			id = uniqueID(headingID(strings.TrimSpace(nodeText(n))), ids)
+			setAttr(n, "id", id)
+		}
+		ids[id] = true
+
+		fn(n, level, id)
+		return false
+	})
+}
+
+// 将平级的标题列表按级别转换成树状结构
+func nestHeadings(headings []*Heading) []*Heading {
+	root := &Heading{Items: make([]*Heading, 0, len(headings))}
+	stack := []*Heading{root}
+
+	for _, h := range headings {
+		for len(stack) > 1 && stack[len(stack)-1].Level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+
+		parent := stack[len(stack)-1]
+		parent.Items = append(parent.Items, h)
+		stack = append(stack, h)
+	}
+
+	return root.Items
+}
+
+// 返回 h2~h4 的级别，其它元素返回 0
+func headingLevel(n *html.Node) int {
+	if n.Type != html.ElementNode {
+		return 0
+	}
+
+	switch n.DataAtom {
+	case atom.H2:
+		return 2
+	case atom.H3:
+		return 3
+	case atom.H4:
+		return 4
+	}
+	return 0
+}
+
+// 根据标题内容生成 id，保留文字和数字，空白字符转换成 -。
+func headingID(text string) string {
+	buf := new(bytes.Buffer)
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && buf.Len() > 0 {
+				buf.WriteByte('-')
+			}
+			dash = false
+			buf.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			dash = true
+		}
+	}
+
+	if buf.Len() == 0 {
+		return "heading"
+	}
+	return buf.String()
+}
+
+// 若 id 已经存在，则在其后加上数字后缀。
+func uniqueID(id string, ids map[string]bool) string {
+	if !ids[id] {
+		return id
+	}
+
+	for i := 2; ; i++ {
+		newID := id + "-" + strconv.Itoa(i)
+		if !ids[newID] {
+			return newID
+		}
+	}
+}
+
+// 获取节点下所有的文本内容
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	buf := new(bytes.Buffer)
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		buf.WriteString(nodeText(child))
+	}
+	return buf.String()
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(n *html.Node, key, val string) {
+	for index, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			n.Attr[index].Val = val
+			return
+		}
+	}
+
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
+
+// 计算阅读时间，以分钟为单位，最少为 1 分钟。
+func readingTime(words int) int {
+	minutes := (words + vars.WordsPerMinute - 1) / vars.WordsPerMinute
+	if minutes < 1 {
+		return 1
+	}
+	return minutes
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestContent_wordCount(t *testing.T) {
+	a := assert.New(t)
+
+	c, err := parseContent("<p>hello world</p><p>中文 go1.11</p><script>var x = 1;</script>")
+	a.NotError(err).NotNil(c)
+	a.Equal(c.wordCount(), 6)
+}
+
+func TestContent_buildTOC(t *testing.T) {
+	a := assert.New(t)
+
+	c, err := parseContent(`<h1>title</h1>
+<h2>Part One</h2>
+<h3>sub 1</h3>
+<h4>sub sub</h4>
+<h3 id="exists">sub 2</h3>
+<h2>Part One</h2>
+<h3>标题</h3>`)
+	a.NotError(err).NotNil(c)
+
+	toc := c.buildTOC()
+	a.Equal(len(toc), 2)
+	a.Equal(toc[0].ID, "part-one").Equal(toc[0].Text, "Part One").Equal(toc[0].Level, 2)
+	a.Equal(len(toc[0].Items), 2)
+	a.Equal(toc[0].Items[0].ID, "sub-1")
+	a.Equal(toc[0].Items[0].Items[0].ID, "sub-sub")
+	a.Equal(toc[0].Items[1].ID, "exists")
+	a.Equal(toc[1].ID, "part-one-2")
+	a.Equal(toc[1].Items[0].ID, "标题")
+
+	html, err := c.String()
+	a.NotError(err)
+	a.Equal(html, `<h1>title</h1>
+<h2 id="part-one">Part One</h2>
+<h3 id="sub-1">sub 1</h3>
+<h4 id="sub-sub">sub sub</h4>
+<h3 id="exists">sub 2</h3>
+<h2 id="part-one-2">Part One</h2>
+<h3 id="标题">标题</h3>`)
+}
+
+func TestHeadingID(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(headingID("Hello, World!"), "hello-world")
+	a.Equal(headingID("  a -- b  "), "a-b")
+	a.Equal(headingID("!!!"), "heading")
+}
+
+func TestReadingTime(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(readingTime(0), 1)
+	a.Equal(readingTime(300), 1)
+	a.Equal(readingTime(301), 2)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
 	"github.com/caixw/gitype/path"
 	"github.com/caixw/gitype/vars"
 	"golang.org/x/text/language"
@@ -27,57 +28,171 @@ type Data struct {
 	// Etag 表示 根据 Updated 生成的 etag 字符串
 	Etag string
 
-	SiteName    string
-	Subtitle    string
-	Beian       string           // 备案号
-	Uptime      time.Time        // 上线时间
-	PageSize    int              // 每页显示的数量
-	Type        string           // 页面的 mime type 类型
-	Icon        *Icon            // 程序默认的图标
-	Menus       []*Link          // 导航菜单
-	Author      *Author          // 默认作者信息
-	License     *Link            // 默认版权信息
-	Pages       map[string]*Page // 各个页面的自定义内容
-	LanguageTag language.Tag
+	SiteName     string
+	Subtitle     string
+	Beian        string           // 备案号
+	Uptime       time.Time        // 上线时间
+	PageSize     int              // 每页显示的数量
+	Type         string           // 页面的 mime type 类型
+	Icon         *Icon            // 程序默认的图标
+	Menus        []*Link          // 导航菜单
+	Author       *Author          // 默认作者信息
+	License      *Link            // 默认版权信息
+	Pages        map[string]*Page // 各个页面的自定义内容
+	LanguageTag  language.Tag
+	LanguageName string       // 语言的名称，用于切换语言
+	Extra        loader.Extra // config.yaml 中的自定义字段
+
+	// 网站的所有语言版本，默认语言在最前，包括当前数据本身。
+	// 只有一种语言时为空。
+	Languages []*Data
 
 	outdatedServer *outdatedServer
 
-	Tags     []*Tag
-	Series   []*Tag
-	Links    []*Link
-	Posts    []*Post
-	Archives []*Archive
-	Theme    *Theme // 当前主题
+	Tags        []*Tag
+	Series      []*Tag
+	Authors     []*Author // authors.yaml 中的作者
+	Links       []*Link
+	Posts       []*Post
+	Archives    []*Archive
+	SinglePages []*SinglePage    // pages 目录下的独立页面
+	Calendar    []*ArchivePeriod // 按年的存档页，每一年又包含了按月的存档页
+	Theme       *Theme           // 当前主题
+
+	// 重定向规则，包含 redirects.yaml 中的内容和文章的 aliases
+	Redirects []*Redirect
+
+	Permalinks *Permalinks     // 各类页面地址的格式
+	routes     map[string]bool // 所有由程序生成的页面地址
 
 	Opensearch        *Feed
 	Sitemap           *Feed
 	RSS               *Feed
 	Atom              *Feed
 	Manifest          *Feed
+	Highlight         *Feed  // 代码高亮的样式表
 	ServiceWorker     []byte // service worker 的内容
 	ServiceWorkerPath string // service worker 的 URL
 
-	Matcher *search.Matcher
+	matchers map[language.Tag]*search.Matcher // 各个语言的搜索匹配器
 }
 
 // Load 函数用于加载一份新的数据。
-func Load(path *path.Path) (*Data, error) {
-	conf, err := loader.LoadConfig(path)
+//
+// url 为网站的地址，为空时使用 web.yaml 中的配置。
+// 配置了其它语言版本时，返回的是默认语言的数据，所有语言的数据可以通过 Languages 获取。
+func Load(path *path.Path, url string) (*Data, error) {
+	conf, err := loader.LoadConfig(path, url)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := loadLanguages(path, url, conf)
+	if err != nil {
+		// 以网站默认语言输出错误信息
+		return nil, helper.LocalizeError(err, conf.LanguageTag)
+	}
+
+	return d, nil
+}
+
+// 加载 conf 对应的默认语言以及其它所有语言版本的数据
+func loadLanguages(path *path.Path, url string, conf *loader.Config) (*Data, error) {
+	// 所有语言的文章都在 posts 目录下，只加载一次。
+	posts, err := loader.LoadPosts(path, conf)
+	if err != nil {
+		return nil, err
+	}
+	groups := groupPosts(conf, posts)
+
+	pages, err := loader.LoadSinglePages(path)
 	if err != nil {
 		return nil, err
 	}
+	pageGroups := groupSinglePages(conf, pages)
+
+	d, err := load(path, conf, groups[conf.Language], pageGroups[conf.Language], true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(conf.Languages) > 0 {
+		d.Languages = make([]*Data, 0, len(conf.Languages)+1)
+		d.Languages = append(d.Languages, d)
+
+		for _, lang := range conf.Languages {
+			c, err := loader.LoadLanguageConfig(path, url, lang)
+			if err != nil {
+				return nil, err
+			}
+
+			ld, err := load(path, c, groups[lang.Tag], pageGroups[lang.Tag], false)
+			if err != nil {
+				return nil, err
+			}
+			d.Languages = append(d.Languages, ld)
+		}
 
+		// 所有语言版本共用同一个切片
+		for _, ld := range d.Languages {
+			ld.Languages = d.Languages
+		}
+
+		if err := d.buildTranslations(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ld := range d.languages() {
+		if err := ld.buildMenus(d); err != nil {
+			return nil, err
+		}
+	}
+
+	// 所有数据都加载成功之后，才启动定时服务，防止出错时需要释放。
+	for _, ld := range d.languages() {
+		ld.initOutdatedServer(conf)
+	}
+
+	return d, nil
+}
+
+// 加载 conf 对应语言的数据，ps 和 pages 分别为该语言下的所有文章和独立页面。
+//
+// redirects.yaml 的内容只在默认语言中加载，即 main 为 true 时。
+func load(path *path.Path, conf *loader.Config, ps []*loader.Post, pages []*loader.SinglePage, main bool) (*Data, error) {
 	tags, err := loadTags(path, conf)
 	if err != nil {
 		return nil, err
 	}
 
+	authors, err := loadAuthors(path, conf)
+	if err != nil {
+		return nil, err
+	}
+	author := &Author{Author: *conf.Author}
+
 	links, err := loader.LoadLinks(path)
 	if err != nil {
 		return nil, err
 	}
 
-	posts, err := loadPosts(path, tags, conf)
+	var redirects []*Redirect
+	if main {
+		if redirects, err = loader.LoadRedirects(path); err != nil {
+			return nil, err
+		}
+	}
+
+	posts, err := loadPosts(path, tags, authors, author, conf, ps)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range authors { // 作者页与首页的文章顺序保持一致
+		sortPosts(a.Posts)
+	}
+
+	singles, err := loadSinglePages(path, conf, pages)
 	if err != nil {
 		return nil, err
 	}
@@ -87,43 +202,129 @@ func Load(path *path.Path) (*Data, error) {
 		return nil, err
 	}
 
+	if err := checkExtra(path, conf, theme, tags, posts); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	d := &Data{
 		path:    path,
 		Created: now,
 
-		SiteName:    conf.Title,
-		Subtitle:    conf.Subtitle,
-		Beian:       conf.Beian,
-		Uptime:      conf.Uptime,
-		PageSize:    conf.PageSize,
-		Type:        conf.Type,
-		Icon:        conf.Icon,
-		Menus:       conf.Menus,
-		Pages:       conf.Pages,
-		LanguageTag: conf.LanguageTag,
-
-		Tags:  tags,
-		Links: links,
-		Posts: posts,
-		Theme: theme,
-
-		Matcher: search.New(conf.LanguageTag, search.Loose),
+		SiteName:     conf.Title,
+		Subtitle:     conf.Subtitle,
+		Beian:        conf.Beian,
+		Uptime:       conf.Uptime,
+		PageSize:     conf.PageSize,
+		Type:         conf.Type,
+		Icon:         conf.Icon,
+		Menus:        conf.Menus,
+		Extra:        conf.Extra,
+		Author:       author,
+		Pages:        conf.Pages,
+		LanguageTag:  conf.LanguageTag,
+		LanguageName: conf.LanguageName,
+
+		Tags:        tags,
+		Authors:     authors,
+		Links:       links,
+		Posts:       posts,
+		SinglePages: singles,
+		Theme:       theme,
+		Redirects:   redirects,
+		Permalinks:  conf.Permalinks,
 	}
 
+	d.initMatchers()
+
 	if err := d.sanitize(conf); err != nil {
 		return nil, err
 	}
 
-	d.initOutdatedServer(conf)
+	// 相关文章需要用到文章的内容，所以最后才隐藏受保护文章的内容。
+	for _, post := range d.Posts {
+		if post.Protection != nil {
+			post.Protection.lock(post)
+		}
+	}
 
 	d.setUpdated(now)
 	return d, nil
 }
 
+// 将文章按语言分组，language 与 languages 中的语言标签相同的，
+// 归入该语言，其它的都归入默认语言。
+func groupPosts(conf *loader.Config, posts []*loader.Post) map[string][]*loader.Post {
+	groups := make(map[string][]*loader.Post, len(conf.Languages)+1)
+
+	for _, post := range posts {
+		lang := groupLanguage(conf, post.Language)
+		groups[lang] = append(groups[lang], post)
+	}
+
+	return groups
+}
+
+// 按语言对独立页面进行分组，规则与 groupPosts 相同。
+func groupSinglePages(conf *loader.Config, pages []*loader.SinglePage) map[string][]*loader.SinglePage {
+	groups := make(map[string][]*loader.SinglePage, len(conf.Languages)+1)
+
+	for _, page := range pages {
+		lang := groupLanguage(conf, page.Language)
+		groups[lang] = append(groups[lang], page)
+	}
+
+	return groups
+}
+
+// 获取 lang 所属的分组，不是 languages 中的语言都归于默认语言。
+func groupLanguage(conf *loader.Config, lang string) string {
+	for _, l := range conf.Languages {
+		if l.Tag == lang {
+			return l.Tag
+		}
+	}
+	return conf.Language
+}
+
+// 为网站的语言以及文章用到的语言分别生成搜索用的匹配器
+func (d *Data) initMatchers() {
+	d.matchers = map[language.Tag]*search.Matcher{
+		d.LanguageTag: search.New(d.LanguageTag, search.Loose),
+	}
+
+	for _, post := range d.Posts {
+		if _, found := d.matchers[post.LanguageTag]; !found {
+			d.matchers[post.LanguageTag] = search.New(post.LanguageTag, search.Loose)
+		}
+	}
+}
+
+// Matcher 获取 tag 语言的搜索匹配器，不存在时返回网站默认语言的匹配器。
+func (d *Data) Matcher(tag language.Tag) *search.Matcher {
+	if m, found := d.matchers[tag]; found {
+		return m
+	}
+	return d.matchers[d.LanguageTag]
+}
+
+// 所有的语言版本，只有一种语言时，返回只包含 d 的切片。
+func (d *Data) languages() []*Data {
+	if len(d.Languages) == 0 {
+		return []*Data{d}
+	}
+	return d.Languages
+}
+
 // Free 释放数据内容
+//
+// 包括所有语言版本的数据。
 func (d *Data) Free() {
-	d.outdatedServer.stop()
+	for _, ld := range d.languages() {
+		if ld.outdatedServer != nil {
+			ld.outdatedServer.stop()
+		}
+	}
 }
 
 // 调整更新时间
@@ -138,18 +339,30 @@ func (d *Data) sanitize(conf *loader.Config) error {
 		return err
 	}
 
-	// 过滤空标签
+	mergeTagPosts(d.Tags)
+
+	// 过滤空标签，子标签的文章已经合并到父标签，所以父标签不会比子标签先被过滤。
 	tags := make([]*Tag, 0, len(d.Tags))
 	for _, tag := range d.Tags {
 		if len(tag.Posts) == 0 {
 			continue
 		}
 
+		children := make([]*Tag, 0, len(tag.Children))
+		for _, child := range tag.Children {
+			if len(child.Posts) > 0 {
+				children = append(children, child)
+			}
+		}
+		tag.Children = children
+
 		tags = append(tags, tag)
 	}
 
 	// 最后才分离标签和专题
 	d.Tags, d.Series = splitTags(tags)
+	buildTagStats(d.Tags)
+	buildTagStats(d.Series)
 
 	return d.buildData(conf)
 }
@@ -162,12 +375,18 @@ func (d *Data) buildData(conf *loader.Config) (err error) {
 		err = fn(conf)
 	}
 
+	errFilter(d.buildSeries)
+	errFilter(d.buildRelated)
 	errFilter(d.buildArchives)
 	errFilter(d.buildOpensearch)
 	errFilter(d.buildSitemap)
 	errFilter(d.buildRSS)
 	errFilter(d.buildAtom)
+	errFilter(d.buildAuthorFeeds)
 	errFilter(d.buildManifest)
+	errFilter(d.buildHighlight)
 	errFilter(d.buildSW)
+	errFilter(d.buildRoutes)    // 需要用到其它页面的地址，所以放在最后
+	errFilter(d.buildRedirects) // 需要用到 buildRoutes 的结果
 	return err
 }
@@ -7,9 +7,12 @@ package data
 import (
 	"testing"
 
+	"github.com/caixw/gitype/helper"
 	"github.com/caixw/gitype/path"
+	"github.com/caixw/gitype/vars"
 	"github.com/issue9/assert"
 	"github.com/issue9/web"
+	"golang.org/x/text/language"
 )
 
 var testdataPath = path.New("../testdata")
@@ -22,12 +25,18 @@ func TestMain(m *testing.M) {
 	m.Run()
 }
 
+// 加载 testdata 下的数据，各功能的测试可以从中检测加载之后的结果。
+func loadTestdata(a *assert.Assertion) *Data {
+	d, err := Load(testdataPath, "")
+	a.NotError(err).NotNil(d)
+	return d
+}
+
 func TestLoad(t *testing.T) {
 	a := assert.New(t)
-	d, err := Load(testdataPath)
-	a.NotError(err).NotNil(d)
+	d := loadTestdata(a)
 
-	a.Equal(len(d.Posts), 2)
+	a.Equal(len(d.Posts), 3)
 
 	// theme
 	a.NotNil(d.Theme)
@@ -39,3 +48,56 @@ func TestLoad(t *testing.T) {
 	a.Equal(d.Atom.URL, "/atom.xml")
 	a.Nil(d.Sitemap)
 }
+
+func TestLoad_languages(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.Equal(len(d.Languages), 2).Equal(d.Languages[0], d)
+	en := d.Languages[1]
+	a.Equal(en.Languages, d.Languages).
+		Equal(en.SiteName, "title-en").
+		Equal(en.LanguageName, "English").
+		Equal(en.Permalinks.PostsURL(1), "/en/").
+		Equal(en.Atom.URL, "/en/atom.xml").
+		Empty(en.Redirects)
+	a.Equal(en.Pages[vars.PageTags].Title, "Tags | title-en") // 按语言重新生成页面的默认标题
+
+	a.Equal(len(en.Posts), 1)
+	post := en.Posts[0]
+	a.Equal(post.Permalink, "/en/posts/post1.html").
+		Equal(post.LanguageTag, language.English)
+
+	// translations
+	a.Equal(d.Posts[0].Translations, []*Post{post})
+	a.Equal(post.Translations, []*Post{d.Posts[0]})
+	a.Equal(post.Translation(d), d.Posts[0]).
+		Equal(post.Translation(en), post).
+		Nil(d.Posts[1].Translation(en))
+
+	// matcher
+	a.NotNil(d.Matcher(language.English)).
+		Equal(d.Matcher(language.English), d.Matcher(d.LanguageTag))
+}
+
+func TestData_buildTranslations(t *testing.T) {
+	a := assert.New(t)
+
+	p1 := &Post{Slug: "p1", translationKey: "key", meta: "p1/meta.yaml"}
+	p2 := &Post{Slug: "p2", translationKey: "key", meta: "p2/meta.en.yaml"}
+	p3 := &Post{Slug: "p3", translationKey: "key", meta: "p3/meta.en.yaml"}
+	en := &Data{Posts: []*Post{p2}}
+	d := &Data{Posts: []*Post{p1}}
+	d.Languages = []*Data{d, en}
+
+	a.NotError(d.buildTranslations())
+	a.Equal(p1.Translations, []*Post{p2})
+	a.Equal(p2.Translations, []*Post{p1})
+
+	// 错误信息指向实际加载的 meta.en.yaml
+	en.Posts = append(en.Posts, p3)
+	err := d.buildTranslations()
+	a.Error(err)
+	ferr, ok := err.(*helper.FieldError)
+	a.True(ok).Equal(ferr.File, "p3/meta.en.yaml")
+}
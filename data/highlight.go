@@ -0,0 +1,153 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	chromahtml "github.com/alecthomas/chroma/formatters/html"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/issue9/utils"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/vars"
+)
+
+const highlightClass = "chroma"
+
+// 代码块中表示语言的 class 前缀
+var languagePrefixes = []string{"language-", "lang-"}
+
+// 只输出带 class 的 span 元素，pre 和 code 保持文章中原有的元素。
+var highlightFormatter = chromahtml.New(chromahtml.WithClasses(), chromahtml.PreventSurroundingPre())
+
+// 生成代码高亮的样式表
+//
+// 样式表的地址位于主题目录之下，主题中的同名文件会被其覆盖，所以此时直接报错。
+func (d *Data) buildHighlight(conf *loader.Config) error {
+	if conf.Highlight == nil {
+		return nil
+	}
+
+	if utils.FileExists(d.path.ThemesPath(d.Theme.ID, vars.HighlightFilename)) {
+		return &helper.FieldError{File: d.path.MetaConfigFile, Message: "与主题中的 highlight.css 冲突", Field: "highlight"}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := highlightFormatter.WriteCSS(buf, styles.Get(conf.Highlight.Style)); err != nil {
+		return err
+	}
+
+	d.Highlight = &Feed{
+		URL:     conf.Permalinks.ThemeURL(vars.HighlightFilename),
+		Type:    "text/css",
+		Content: buf.Bytes(),
+	}
+
+	return nil
+}
+
+// 高亮所有 pre>code 中指定了语言的代码块。
+//
+// 无法识别的语言，保持原样。
+func (c *content) highlight() error {
+	codes := make([]*html.Node, 0, 10)
+	c.walk(func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.DataAtom != atom.Pre {
+			return true
+		}
+
+		if code := preCode(n); code != nil {
+			codes = append(codes, code)
+		}
+		return false
+	})
+
+	for _, code := range codes {
+		lexer := lexers.Get(codeLanguage(code))
+		if lexer == nil {
+			continue
+		}
+
+		iterator, err := chroma.Coalesce(lexer).Tokenise(nil, nodeText(code))
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		if err = highlightFormatter.Format(buf, styles.Fallback, iterator); err != nil {
+			return err
+		}
+
+		nodes, err := html.ParseFragment(buf, code)
+		if err != nil {
+			return err
+		}
+
+		for code.FirstChild != nil {
+			code.RemoveChild(code.FirstChild)
+		}
+		for _, n := range nodes {
+			code.AppendChild(n)
+		}
+		addClass(code.Parent, highlightClass)
+	}
+
+	return nil
+}
+
+// 若 pre 中只包含一个 code 元素，则返回该元素。
+func preCode(pre *html.Node) *html.Node {
+	var code *html.Node
+	for n := pre.FirstChild; n != nil; n = n.NextSibling {
+		switch {
+		case n.Type == html.ElementNode && n.DataAtom == atom.Code && code == nil:
+			code = n
+		case n.Type == html.TextNode && strings.TrimSpace(n.Data) == "":
+		default:
+			return nil
+		}
+	}
+
+	return code
+}
+
+// 从 class 属性中获取语言名称，比如 language-go 返回 go
+func codeLanguage(code *html.Node) string {
+	for _, class := range strings.Fields(getAttr(code, "class")) {
+		for _, prefix := range languagePrefixes {
+			if strings.HasPrefix(class, prefix) {
+				return strings.TrimPrefix(class, prefix)
+			}
+		}
+	}
+
+	return ""
+}
+
+func addClass(n *html.Node, class string) {
+	addToken(n, "class", class)
+}
+
+// 向以空格分隔的属性值中添加一项内容，若已经存在，则不作修改。
+func addToken(n *html.Node, key, token string) {
+	val := getAttr(n, key)
+	for _, t := range strings.Fields(val) {
+		if t == token {
+			return
+		}
+	}
+
+	if val != "" {
+		token = val + " " + token
+	}
+	setAttr(n, key, token)
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+	"github.com/caixw/gitype/vars"
+)
+
+func TestContent_highlight(t *testing.T) {
+	a := assert.New(t)
+
+	c, err := parseContent(`<pre><code class="language-go">package main</code></pre>
+<pre><code class="language-not-exists">abc</code></pre>
+<pre><code>def</code></pre>
+<pre>ghi<code class="language-go">jkl</code></pre>`)
+	a.NotError(err).NotNil(c)
+	a.NotError(c.highlight())
+
+	html, err := c.String()
+	a.NotError(err)
+	a.True(strings.HasPrefix(html, `<pre class="chroma"><code class="language-go"><span class="kn">package</span>`))
+	a.True(strings.Contains(html, `<pre><code class="language-not-exists">abc</code></pre>`))
+	a.True(strings.Contains(html, `<pre><code>def</code></pre>`))
+	a.True(strings.Contains(html, `<pre>ghi<code class="language-go">jkl</code></pre>`))
+}
+
+func TestCodeLanguage(t *testing.T) {
+	a := assert.New(t)
+
+	c, err := parseContent(`<code class="x language-go">`)
+	a.NotError(err)
+	a.Equal(codeLanguage(c.nodes[0]), "go")
+
+	c, err = parseContent(`<code class="lang-sh">`)
+	a.NotError(err)
+	a.Equal(codeLanguage(c.nodes[0]), "sh")
+
+	c, err = parseContent(`<code class="go">`)
+	a.NotError(err)
+	a.Equal(codeLanguage(c.nodes[0]), "")
+}
+
+func TestData_buildHighlight(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gitype")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	p := path.New(dir)
+	a.NotError(os.MkdirAll(p.ThemesPath("t1"), os.ModePerm))
+	conf := &loader.Config{
+		Highlight:  &loader.Highlight{Style: "github"},
+		Permalinks: &loader.Permalinks{},
+	}
+
+	d := &Data{path: p, Theme: &Theme{Theme: loader.Theme{ID: "t1"}}}
+	a.NotError(d.buildHighlight(conf))
+	a.NotNil(d.Highlight).
+		Equal(d.Highlight.URL, "/themes/"+vars.HighlightFilename).
+		NotEmpty(d.Highlight.Content)
+
+	// 主题中存在同名文件
+	file := p.ThemesPath("t1", vars.HighlightFilename)
+	a.NotError(ioutil.WriteFile(file, []byte("css"), os.ModePerm))
+	err = d.buildHighlight(conf)
+	a.Error(err)
+	ferr, ok := err.(*helper.FieldError)
+	a.True(ok).Equal(ferr.Field, "highlight")
+}
@@ -0,0 +1,226 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/url"
+	"os"
+	stdpath "path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+)
+
+// Image 表示一张图片及其不同宽度的版本
+type Image struct {
+	URL    string // 原图地址
+	Width  int    // 原图的宽度
+	Height int    // 原图的高度
+	Srcset string // 所有版本的地址，可以直接用于 img.srcset 属性
+}
+
+// 生成图片的不同宽度版本
+//
+// 生成的图片保存在 path.CacheDir 之下，
+// 若缓存的图片比原图新，则不会重新生成。
+type imageProcessor struct {
+	path   *path.Path
+	conf   *loader.Images
+	urls   *loader.Permalinks
+	images map[string]*Image // 以 URL 为键名，防止同一图片被多次处理
+}
+
+func newImageProcessor(path *path.Path, conf *loader.Images, urls *loader.Permalinks) *imageProcessor {
+	return &imageProcessor{
+		path:   path,
+		conf:   conf,
+		urls:   urls,
+		images: make(map[string]*Image, 100),
+	}
+}
+
+// 获取 url 对应的图片信息
+//
+// 只处理文章资源目录下的 JPEG 和 PNG 图片，其它情况下返回 nil。
+func (ip *imageProcessor) image(url string) (*Image, error) {
+	if img, found := ip.images[url]; found {
+		return img, nil
+	}
+
+	img, err := ip.load(url)
+	if err != nil {
+		return nil, err
+	}
+
+	ip.images[url] = img
+	return img, nil
+}
+
+func (ip *imageProcessor) load(url string) (*Image, error) {
+	prefix := ip.urls.AssetURL("")
+	clean := stdpath.Clean(url)
+	if !strings.HasPrefix(clean, prefix) {
+		return nil, nil
+	}
+	rel := strings.TrimPrefix(clean, prefix)
+
+	ext := strings.ToLower(stdpath.Ext(rel))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return nil, nil
+	}
+
+	filename := filepath.Join(ip.path.PostsDir, filepath.FromSlash(rel))
+	stat, err := os.Stat(filename)
+	if os.IsNotExist(err) { // 不存在的图片不作处理，由客户端自行处理
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// 解码所需的内存与像素数成正比，在解码之前拒绝过大的图片。
+	if int64(cfg.Width)*int64(cfg.Height) > int64(ip.conf.MaxPixels) {
+		return nil, helper.Errorf("图片 %s 的像素数超过了 images.maxPixels 的限制", url)
+	}
+
+	img := &Image{
+		URL:    url,
+		Width:  cfg.Width,
+		Height: cfg.Height,
+	}
+
+	var src image.Image // 延迟解码，只在需要生成新图片时才解码
+	srcset := make([]string, 0, len(ip.conf.Widths)+1)
+	base := strings.TrimSuffix(rel, stdpath.Ext(rel))
+	for _, width := range ip.conf.Widths {
+		if width >= cfg.Width {
+			break
+		}
+
+		name := base + "-" + strconv.Itoa(width) + "w" + ext
+		cache := ip.path.ImagePath(name)
+		if s, err := os.Stat(cache); err != nil || s.ModTime().Before(stat.ModTime()) {
+			if src == nil {
+				if _, err = file.Seek(0, 0); err != nil {
+					return nil, err
+				}
+				if src, _, err = image.Decode(file); err != nil {
+					return nil, err
+				}
+			}
+
+			height := cfg.Height * width / cfg.Width
+			if err = ip.resize(src, cache, ext, width, height); err != nil {
+				return nil, err
+			}
+		}
+
+		srcset = append(srcset, ip.urls.ImageURL(name)+" "+strconv.Itoa(width)+"w")
+	}
+	srcset = append(srcset, url+" "+strconv.Itoa(cfg.Width)+"w")
+	img.Srcset = strings.Join(srcset, ", ")
+
+	return img, nil
+}
+
+// 将 src 缩放到指定的大小，并保存到 filename
+func (ip *imageProcessor) resize(src image.Image, filename, ext string, width, height int) error {
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if ext == ".png" {
+		return png.Encode(file, dst)
+	}
+	return jpeg.Encode(file, dst, &jpeg.Options{Quality: ip.conf.Quality})
+}
+
+// 为内容中的 img 标签添加 srcset、width、height 和 loading 属性
+//
+// 相对地址的图片，与浏览器的处理方式相同，以文章页面所在的目录作为其根目录。
+func (c *content) rewriteImages(ip *imageProcessor, post *Post) (err error) {
+	c.walk(func(n *html.Node) bool {
+		if err != nil {
+			return false
+		}
+
+		if n.Type != html.ElementNode || n.DataAtom != atom.Img {
+			return true
+		}
+
+		lazyload(n)
+
+		var img *Image
+		img, err = ip.image(postAssetURL(post, getAttr(n, "src")))
+		if err != nil || img == nil {
+			return false
+		}
+
+		if getAttr(n, "srcset") == "" {
+			setAttr(n, "srcset", img.Srcset)
+		}
+		if getAttr(n, "width") == "" && getAttr(n, "height") == "" {
+			setAttr(n, "width", strconv.Itoa(img.Width))
+			setAttr(n, "height", strconv.Itoa(img.Height))
+		}
+		return false
+	})
+
+	return err
+}
+
+// 将文章中的相对地址转换成以文章资源目录为根目录的地址，
+// 比如文章 folder/post 中的 assets/1.png 会被转换成 /posts/folder/post/assets/1.png。
+// 绝对地址、带协议的地址或是无法解析的地址则原样返回。
+func postAssetURL(post *Post, u string) string {
+	if !isRelativeURL(u) {
+		return u
+	}
+
+	base, err := url.Parse(post.permalinks.AssetURL(post.Slug) + "/")
+	if err != nil {
+		return u
+	}
+
+	ref, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	return base.ResolveReference(ref).String()
+}
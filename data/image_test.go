@@ -0,0 +1,93 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/utils"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+func TestImageProcessor_image(t *testing.T) {
+	a := assert.New(t)
+	a.NotError(os.RemoveAll(testdataPath.CacheDir))
+
+	ip := newImageProcessor(testdataPath, &loader.Images{Widths: []int{10, 20, 100}, Quality: 85, MaxPixels: 800}, &loader.Permalinks{})
+
+	img, err := ip.image("/posts/folder/post2/assets/cover.png")
+	a.NotError(err).NotNil(img)
+	a.Equal(img.Width, 40).Equal(img.Height, 20)
+	a.Equal(img.Srcset, "/images/folder/post2/assets/cover-10w.png 10w, /images/folder/post2/assets/cover-20w.png 20w, /posts/folder/post2/assets/cover.png 40w")
+	a.True(utils.FileExists(testdataPath.ImagePath("folder/post2/assets/cover-10w.png")))
+	a.True(utils.FileExists(testdataPath.ImagePath("folder/post2/assets/cover-20w.png")))
+	a.False(utils.FileExists(testdataPath.ImagePath("folder/post2/assets/cover-100w.png")))
+
+	// 缓存
+	img2, err := ip.image("/posts/folder/post2/assets/cover.png")
+	a.NotError(err).Equal(img, img2)
+
+	// 不存在的图片
+	img, err = ip.image("/posts/folder/post2/assets/not-exists.png")
+	a.NotError(err).Nil(img)
+
+	// 非图片
+	img, err = ip.image("/posts/folder/post2/assets/assets.txt")
+	a.NotError(err).Nil(img)
+
+	// 非本地图片
+	img, err = ip.image("https://example.com/posts/cover.png")
+	a.NotError(err).Nil(img)
+
+	// 不能跳出文章目录
+	img, err = ip.image("/posts/../meta/cover.png")
+	a.NotError(err).Nil(img)
+
+	// 超过像素数限制，40 × 20 > 799
+	ip = newImageProcessor(testdataPath, &loader.Images{Widths: []int{10}, Quality: 85, MaxPixels: 799}, &loader.Permalinks{})
+	img, err = ip.image("/posts/folder/post2/assets/cover.png")
+	a.Error(err).Nil(img)
+}
+
+func TestContent_rewriteImages(t *testing.T) {
+	a := assert.New(t)
+
+	ip := newImageProcessor(testdataPath, &loader.Images{Widths: []int{20}, Quality: 85, MaxPixels: 800}, &loader.Permalinks{})
+	c, err := parseContent(`<img src="assets/cover.png" />
+<img src="https://example.com/1.png" loading="eager" />`)
+	a.NotError(err).NotNil(c)
+	post := &Post{Slug: "folder/post2", permalinks: &loader.Permalinks{}}
+	a.NotError(c.rewriteImages(ip, post))
+
+	html, err := c.String()
+	a.NotError(err)
+	a.True(strings.Contains(html, `<img src="assets/cover.png" loading="lazy" srcset="/images/folder/post2/assets/cover-20w.png 20w, /posts/folder/post2/assets/cover.png 40w" width="40" height="20"/>`))
+	a.True(strings.Contains(html, `<img src="https://example.com/1.png" loading="eager"/>`))
+}
+
+func TestPostAssetURL(t *testing.T) {
+	a := assert.New(t)
+
+	post := &Post{Slug: "folder/post2", permalinks: &loader.Permalinks{}}
+	a.Equal(postAssetURL(post, "assets/1.png"), "/posts/folder/post2/assets/1.png")
+	a.Equal(postAssetURL(post, "../1.png"), "/posts/folder/1.png")
+	a.Equal(postAssetURL(post, "/1.png"), "/1.png")
+	a.Equal(postAssetURL(post, "https://example.com/1.png"), "https://example.com/1.png")
+	a.Equal(postAssetURL(post, ""), "")
+}
+
+func TestLoad_images(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	cover := d.Posts[1].Cover
+	a.NotNil(cover)
+	a.Equal(cover.Width, 40).Equal(cover.Height, 20)
+	a.Nil(d.Posts[0].Cover)
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"strconv"
+
+	"github.com/issue9/utils"
+
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+)
+
+// LoadAuthors 加载 authors.yaml 中的作者列表
+//
+// 该文件是可选的，不存在时返回空值。
+func LoadAuthors(path *path.Path) ([]*Author, error) {
+	if !utils.FileExists(path.MetaAuthorsFile) {
+		return nil, nil
+	}
+
+	authors := make([]*Author, 0, 10)
+	if err := helper.LoadYAMLFile(path.MetaAuthorsFile, &authors); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(authors))
+	for index, author := range authors {
+		field := "[" + strconv.Itoa(index) + "]."
+
+		if err := author.sanitizeRegistered(); err != nil {
+			err.File = path.MetaAuthorsFile
+			err.Field = field + err.Field
+			return nil, err
+		}
+
+		if ids[author.ID] {
+			return nil, &helper.FieldError{File: path.MetaAuthorsFile, Message: "重复的值", Field: field + "id"}
+		}
+		ids[author.ID] = true
+	}
+
+	return authors, nil
+}
+
+// 检测 authors.yaml 中的作者
+func (author *Author) sanitizeRegistered() *helper.FieldError {
+	// ID 会作为地址的一部分，与语言标签的要求相同
+	if !languagePattern.MatchString(author.ID) {
+		return &helper.FieldError{Message: "只能包含字母、数字和 -", Field: "id"}
+	}
+
+	if err := author.sanitize(); err != nil {
+		return err
+	}
+
+	for index, link := range author.Links {
+		if err := link.sanitize(); err != nil {
+			err.Field = "links[" + strconv.Itoa(index) + "]." + err.Field
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestLoadAuthors(t *testing.T) {
+	a := assert.New(t)
+
+	authors, err := LoadAuthors(testdataPath)
+	a.NotError(err).Equal(len(authors), 2)
+
+	a.Equal(authors[0].ID, "caixw")
+	a.Equal(authors[0].Bio, "博主")
+	a.Equal(len(authors[0].Links), 1)
+	a.Equal(authors[1].ID, "reader")
+}
+
+func TestAuthor_sanitizeRegistered(t *testing.T) {
+	a := assert.New(t)
+
+	author := &Author{Name: "caixw"}
+	a.Equal(author.sanitizeRegistered().Field, "id")
+
+	author.ID = "caixw/1"
+	a.Equal(author.sanitizeRegistered().Field, "id")
+
+	author.ID = "caixw"
+	a.NotError(author.sanitizeRegistered())
+
+	author.Links = []*Link{{Text: "github"}}
+	a.Equal(author.sanitizeRegistered().Field, "links[0].url")
+}
@@ -6,16 +6,19 @@ package loader
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/issue9/web"
+	l "golang.org/x/text/language"
+
 	"github.com/caixw/gitype/helper"
 	"github.com/caixw/gitype/path"
-	l "golang.org/x/text/language"
 )
 
-// 默认的语言，在配置文件中未指定时，使用此值，
-// 作为默认值，此值最好不要修改，若需要修改，
-// 则最好将诸如 tagTitle 等与语言相关的常量一起修改。
+// 默认的语言，在配置文件中未指定时，使用此值。
+//
+// 诸如 tagTitle 等与语言相关的默认值，都会根据语言进行翻译，翻译内容位于 locale 包中。
 const language = "zh-cmn-Hans"
 
 // Config 配置信息，用于从文件中读取
@@ -23,6 +26,7 @@ type Config struct {
 	Title           string        `yaml:"title"`
 	TitleSeparator  string        `yaml:"titleSeparator"`
 	Language        string        `yaml:"language"`
+	LanguageName    string        `yaml:"languageName,omitempty"` // 语言的名称，用于切换语言，默认为该语言对自身的称呼
 	Subtitle        string        `yaml:"subtitle,omitempty"`
 	Beian           string        `yaml:"beian,omitempty"`
 	Uptime          time.Time     `yaml:"uptime"`
@@ -37,38 +41,78 @@ type Config struct {
 	Outdated        time.Duration `yaml:"outdated,omitempty"`
 	Theme           string        `yaml:"theme"`
 
+	// 自动生成摘要时的长度，以字符计，默认为 200。
+	// 仅在文章未指定摘要且内容中不包含 <!--more--> 时有效。
+	SummarySize int `yaml:"summarySize,omitempty"`
+
 	// 各个页面的一些自定义项，目前支持以下几个元素的修改：
 	// 1) html>head>title
 	// 2) html>head>meta.keywords
 	// 3) html>head>meta.description
 	Pages map[string]*Page `yaml:"pages,omitempty"`
 
+	// 自定义字段，原样传递给模板，可通过 .Site.Extra 访问
+	Extra Extra `yaml:"extra,omitempty"`
+
+	// 受保护文章的分组，键名为组名，值为密码的散列值，可通过 gitype -password 生成。
+	// 文章的 protection 指定为组名时，使用该组的密码。
+	Protections map[string]string `yaml:"protections,omitempty"`
+
+	// 各类页面地址的格式，未指定的使用默认值
+	Permalinks *Permalinks `yaml:"permalinks,omitempty"`
+
 	Archive    *Archive    `yaml:"archive"`
+	Related    *Related    `yaml:"related,omitempty"`
+	Highlight  *Highlight  `yaml:"highlight,omitempty"`
+	Images     *Images     `yaml:"images,omitempty"`
+	Sanitize   *Sanitize   `yaml:"sanitize,omitempty"`
 	RSS        *RSS        `yaml:"rss,omitempty"`
 	Atom       *RSS        `yaml:"atom,omitempty"`
 	Sitemap    *Sitemap    `yaml:"sitemap,omitempty"`
 	Opensearch *Opensearch `yaml:"opensearch,omitempty"`
 	PWA        *PWA        `yaml:"pwa,omitempty"`
 
+	// 文章内容的转换器，按顺序依次执行
+	Transformers []*Transformer `yaml:"transformers,omitempty"`
+
+	// 网站的其它语言版本，默认语言由 language 指定。
+	Languages []*Language `yaml:"languages,omitempty"`
+
 	LanguageTag l.Tag `yaml:"-"`
 }
 
 // LoadConfig 加载配置信息
-func LoadConfig(path *path.Path) (*Config, error) {
+//
+// url 为网站的地址，为空时使用 web.yaml 中的配置。
+func LoadConfig(path *path.Path, url string) (*Config, error) {
+	return loadConfig(path, url, nil)
+}
+
+func loadConfig(path *path.Path, url string, lang *Language) (*Config, error) {
 	conf := &Config{}
 	if err := helper.LoadYAMLFile(path.MetaConfigFile, conf); err != nil {
 		return nil, err
 	}
 
-	if err := conf.sanitize(); err != nil {
+	if url == "" {
+		url = web.URL("")
+	}
+
+	if lang != nil {
+		lang.apply(conf)
+		url = strings.TrimSuffix(url, "/") + "/" + lang.Tag
+	}
+
+	if err := conf.sanitize(url); err != nil {
 		err.File = path.MetaConfigFile
+		err.Language = conf.LanguageTag
 		return nil, err
 	}
 
 	return conf, nil
 }
 
-func (conf *Config) sanitize() *helper.FieldError {
+func (conf *Config) sanitize(url string) *helper.FieldError {
 	if len(conf.Language) == 0 {
 		conf.Language = language
 	}
@@ -79,6 +123,10 @@ func (conf *Config) sanitize() *helper.FieldError {
 	}
 	conf.LanguageTag = tag
 
+	if err := conf.sanitizeLanguages(); err != nil {
+		return err
+	}
+
 	if conf.PageSize <= 0 {
 		return &helper.FieldError{Message: "必须为大于零的整数", Field: "pageSize"}
 	}
@@ -91,6 +139,12 @@ func (conf *Config) sanitize() *helper.FieldError {
 		return &helper.FieldError{Message: "不能为空", Field: "shortDateFormat"}
 	}
 
+	if conf.SummarySize < 0 {
+		return &helper.FieldError{Message: "不能小于 0", Field: "summarySize"}
+	} else if conf.SummarySize == 0 {
+		conf.SummarySize = defaultSummarySize
+	}
+
 	if conf.Outdated < 0 {
 		return &helper.FieldError{Message: "必须大于 0", Field: "outdated"}
 	}
@@ -99,6 +153,15 @@ func (conf *Config) sanitize() *helper.FieldError {
 		conf.Type = contentTypeHTML
 	}
 
+	// permalinks，之后的各个地址都需要加上网站所在的子目录
+	if conf.Permalinks == nil {
+		conf.Permalinks = &Permalinks{}
+	}
+	if err := conf.Permalinks.sanitize(url); err != nil {
+		return err
+	}
+	conf.rebaseURLs()
+
 	// icon
 	if conf.Icon != nil {
 		if err := conf.Icon.sanitize(); err != nil {
@@ -129,10 +192,58 @@ func (conf *Config) sanitize() *helper.FieldError {
 	if conf.Archive == nil {
 		return &helper.FieldError{Message: "不能为空", Field: "archive"}
 	}
-	if err := conf.Archive.sanitize(); err != nil {
+	if err := conf.Archive.sanitize(conf.LanguageTag); err != nil {
 		return err
 	}
 
+	// related
+	if conf.Related != nil {
+		if err := conf.Related.sanitize(); err != nil {
+			return err
+		}
+	}
+
+	// highlight
+	if conf.Highlight != nil {
+		if err := conf.Highlight.sanitize(); err != nil {
+			return err
+		}
+	}
+
+	// images
+	if conf.Images != nil {
+		if err := conf.Images.sanitize(); err != nil {
+			return err
+		}
+	}
+
+	// sanitize
+	if conf.Sanitize != nil {
+		if err := conf.Sanitize.sanitize(); err != nil {
+			return err
+		}
+	}
+
+	// transformers
+	names := make(map[string]bool, len(conf.Transformers))
+	for index, t := range conf.Transformers {
+		field := "transformers[" + strconv.Itoa(index) + "].name"
+		if t.Name == "" {
+			return &helper.FieldError{Message: "不能为空", Field: field}
+		}
+		if names[t.Name] {
+			return &helper.FieldError{Message: "重复的值", Field: field}
+		}
+		names[t.Name] = true
+	}
+
+	// protections
+	for name, hash := range conf.Protections {
+		if !IsPasswordHash(hash) {
+			return &helper.FieldError{Message: "必须为密码的散列值", Field: "protections." + name}
+		}
+	}
+
 	// license
 	if conf.License == nil {
 		return &helper.FieldError{Message: "不能为空", Field: "license"}
@@ -178,6 +289,13 @@ func (conf *Config) sanitize() *helper.FieldError {
 
 	// menus
 	for index, link := range conf.Menus {
+		if link.Page != "" { // 在 data 包中根据独立页面生成 url 和 text
+			if link.URL != "" {
+				return &helper.FieldError{Message: "不能与 page 同时指定", Field: "Menus[" + strconv.Itoa(index) + "].url"}
+			}
+			continue
+		}
+
 		if err := link.sanitize(); err != nil {
 			err.Field = "Menus[" + strconv.Itoa(index) + "]." + err.Field
 			return err
@@ -188,3 +306,54 @@ func (conf *Config) sanitize() *helper.FieldError {
 
 	return nil
 }
+
+// 为配置项中以 / 开头的站内地址加上网站所在的子目录
+//
+// 需要在各个配置项设置默认值之前调用，防止共用的对象被多次处理。
+func (conf *Config) rebaseURLs() {
+	p := conf.Permalinks
+
+	if conf.Icon != nil {
+		conf.Icon.URL = p.URL(conf.Icon.URL)
+	}
+
+	for _, link := range conf.Menus {
+		link.URL = p.URL(link.URL)
+	}
+
+	for _, rss := range []*RSS{conf.RSS, conf.Atom} {
+		if rss == nil {
+			continue
+		}
+
+		rss.URL = p.URL(rss.URL)
+		if rss.Podcast != nil {
+			rss.Podcast.Image = p.URL(rss.Podcast.Image)
+		}
+	}
+
+	if conf.Sitemap != nil {
+		conf.Sitemap.URL = p.URL(conf.Sitemap.URL)
+		conf.Sitemap.XslURL = p.URL(conf.Sitemap.XslURL)
+	}
+
+	if conf.Opensearch != nil {
+		conf.Opensearch.URL = p.URL(conf.Opensearch.URL)
+		if conf.Opensearch.Image != nil {
+			conf.Opensearch.Image.URL = p.URL(conf.Opensearch.Image.URL)
+		}
+	}
+
+	if conf.PWA != nil {
+		conf.PWA.ServiceWorker = p.URL(conf.PWA.ServiceWorker)
+
+		if m := conf.PWA.Manifest; m != nil {
+			m.URL = p.URL(m.URL)
+			m.StartURL = p.URL(m.StartURL)
+			m.Scope = p.URL(m.Scope)
+			for _, icon := range m.Icons {
+				icon.URL = p.URL(icon.URL)
+			}
+		}
+	}
+}
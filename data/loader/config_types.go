@@ -4,7 +4,17 @@
 
 package loader
 
-import "github.com/caixw/gitype/helper"
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/styles"
+	l "golang.org/x/text/language"
+
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/locale"
+)
 
 // 归档的类型
 const (
@@ -18,12 +28,28 @@ const (
 	ArchiveOrderAsc  = "asc"
 )
 
+// 相关文章中专题标签的默认权重倍数
+const defaultRelatedSeries float64 = 2
+
 // RSS RSS 和 Atom 相关的配置项
 type RSS struct {
 	Title string `yaml:"title"`
 	URL   string `yaml:"url"`
 	Type  string `yaml:"type,omitempty"`
 	Size  int    `yaml:"size"` // 显示数量
+
+	// 播客的相关配置，仅对 RSS 有效，若不需要，则不指定该值即可。
+	Podcast *Podcast `yaml:"podcast,omitempty"`
+}
+
+// Podcast 播客的相关配置，对应 iTunes 的 podcast 命名空间中的元素
+type Podcast struct {
+	Image       string `yaml:"image"`                 // 封面图片，对应 itunes:image
+	Category    string `yaml:"category"`              // 分类，对应 itunes:category
+	Subcategory string `yaml:"subcategory,omitempty"` // 子分类
+	Explicit    bool   `yaml:"explicit,omitempty"`    // 是否包含成人内容
+	Author      string `yaml:"author,omitempty"`      // 作者，默认为 config.author.name
+	Email       string `yaml:"email,omitempty"`       // 所有者的邮箱，默认为 config.author.email
 }
 
 // Opensearch opensearch 相关的配置
@@ -53,13 +79,80 @@ type Sitemap struct {
 	PostChangefreq string  `yaml:"postChangefreq"`
 }
 
+// Related 相关文章的配置内容
+type Related struct {
+	Size int `yaml:"size"` // 每篇文章最多显示的相关文章数量
+
+	// 同属一个专题时的权重倍数，不能小于 0，不指定则为 2，
+	// 即专题标签的权重是同等情况下普通标签的 2 倍；
+	// 为 1 表示与普通标签相同，为 0 表示不计算专题标签的权重。
+	Series *float64 `yaml:"series,omitempty"`
+
+	// 文本相似度的权重，为 0 表示不计算文本相似度。
+	// 文本相似度的取值范围为 [0,1]，最终会乘以此值再加到总的权重中。
+	Text float64 `yaml:"text,omitempty"`
+}
+
+// Highlight 代码高亮的相关配置
+//
+// 文章中所有 pre>code.language-xxx 的代码块都会在加载时被高亮，
+// 对应的样式表由程序生成，主题可以通过 Site.Highlight 引用。
+type Highlight struct {
+	Style string `yaml:"style"` // 样式名称，可用的值可参考 https://github.com/alecthomas/chroma
+}
+
+// Images 响应式图片的相关配置
+//
+// 文章的封面图片以及文章资源目录下的 JPEG 和 PNG 图片，
+// 会在加载时生成指定宽度的版本，保存在缓存目录中。
+type Images struct {
+	Widths    []int `yaml:"widths"`              // 需要生成的图片宽度，比原图宽的会被忽略
+	Quality   int   `yaml:"quality,omitempty"`   // JPEG 的压缩质量，取值为 [1,100]，默认为 85
+	Rewrite   bool  `yaml:"rewrite,omitempty"`   // 是否改写文章内容中的 img 标签，添加 srcset 等属性
+	MaxPixels int   `yaml:"maxPixels,omitempty"` // 图片允许的最大像素数（宽 × 高），超过的图片不会被解码，默认为 defaultImageMaxPixels
+}
+
+// 图片默认允许的最大像素数，解码后约占 100MB 内存。
+const defaultImageMaxPixels = 25000000
+
+// Sanitize 文章内容的过滤规则
+//
+// 指定该值之后，文章内容中只能包含默认允许的元素和属性，以及在此额外指定的内容，
+// 其它元素和属性都会被去掉。iframe、script 和 embed 只有在其 src 指向 hosts 中的域名时才会被保留。
+type Sanitize struct {
+	Elements   []string `yaml:"elements,omitempty"`   // 额外允许的元素
+	Attributes []string `yaml:"attributes,omitempty"` // 额外允许的属性
+	Hosts      []string `yaml:"hosts,omitempty"`      // 允许嵌入内容的域名，比如 www.youtube.com
+}
+
+// Transformer 文章内容转换器的配置
+//
+// 转换器按在配置文件中的顺序依次执行，可用的转换器由 data 包定义。
+type Transformer struct {
+	Name    string            `yaml:"name"`
+	Options map[string]string `yaml:"options,omitempty"` // 转换器的参数，由各个转换器自行解释
+}
+
 // Archive 存档页的配置内容
 type Archive struct {
 	Order  string `yaml:"order"`            // 排序方式
 	Type   string `yaml:"type,omitempty"`   // 存档的分类方式，可以按年或是按月
-	Format string `yaml:"format,omitempty"` // 标题的格式化字符串
+	Format string `yaml:"format,omitempty"` // 标题的格式化字符串，默认根据 type 和语言决定
+
+	// 按年和按月存档页的标题格式，默认根据语言决定
+	YearFormat  string `yaml:"yearFormat,omitempty"`
+	MonthFormat string `yaml:"monthFormat,omitempty"`
+
+	// 存档中是否包含受保护的文章，默认为 false
+	Protected bool `yaml:"protected,omitempty"`
 }
 
+// 归档标题的默认格式，会根据 config.yaml 中的 language 进行翻译。
+const (
+	archiveYearFormat  = "2006 年"
+	archiveMonthFormat = "2006 年 01 月"
+)
+
 func (rss *RSS) sanitize(conf *Config, typ string) *helper.FieldError {
 	if rss.Size <= 0 {
 		return &helper.FieldError{Message: "必须大于 0", Field: typ + ".Size"}
@@ -81,6 +174,37 @@ func (rss *RSS) sanitize(conf *Config, typ string) *helper.FieldError {
 		rss.Title = conf.Title
 	}
 
+	if rss.Podcast != nil {
+		if typ != "rss" {
+			return &helper.FieldError{Message: "仅 rss 支持该值", Field: typ + ".podcast"}
+		}
+
+		if err := rss.Podcast.sanitize(conf); err != nil {
+			err.Field = typ + ".podcast." + err.Field
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Podcast) sanitize(conf *Config) *helper.FieldError {
+	if len(p.Image) == 0 {
+		return &helper.FieldError{Message: "不能为空", Field: "image"}
+	}
+
+	if len(p.Category) == 0 {
+		return &helper.FieldError{Message: "不能为空", Field: "category"}
+	}
+
+	if len(p.Author) == 0 {
+		p.Author = conf.Author.Name
+	}
+
+	if len(p.Email) == 0 {
+		p.Email = conf.Author.Email
+	}
+
 	return nil
 }
 
@@ -128,7 +252,87 @@ func (s *Sitemap) sanitize() *helper.FieldError {
 	return nil
 }
 
-func (a *Archive) sanitize() *helper.FieldError {
+func (r *Related) sanitize() *helper.FieldError {
+	switch {
+	case r.Size <= 0:
+		return &helper.FieldError{Message: "必须大于 0", Field: "related.size"}
+	case r.Series != nil && *r.Series < 0:
+		return &helper.FieldError{Message: "不能小于 0", Field: "related.series"}
+	case r.Text < 0:
+		return &helper.FieldError{Message: "不能小于 0", Field: "related.text"}
+	}
+
+	if r.Series == nil {
+		series := defaultRelatedSeries
+		r.Series = &series
+	}
+
+	return nil
+}
+
+func (h *Highlight) sanitize() *helper.FieldError {
+	if len(h.Style) == 0 {
+		return &helper.FieldError{Message: "不能为空", Field: "highlight.style"}
+	}
+
+	if _, found := styles.Registry[h.Style]; !found {
+		return &helper.FieldError{Message: "不存在的样式", Field: "highlight.style"}
+	}
+
+	return nil
+}
+
+func (s *Sanitize) sanitize() *helper.FieldError {
+	lists := []struct {
+		field string
+		items []string
+	}{
+		{"sanitize.elements", s.Elements},
+		{"sanitize.attributes", s.Attributes},
+		{"sanitize.hosts", s.Hosts},
+	}
+
+	for _, list := range lists {
+		for index, item := range list.items {
+			item = strings.ToLower(strings.TrimSpace(item))
+			if item == "" {
+				return &helper.FieldError{Message: "不能为空", Field: list.field + "[" + strconv.Itoa(index) + "]"}
+			}
+			list.items[index] = item
+		}
+	}
+
+	return nil
+}
+
+func (img *Images) sanitize() *helper.FieldError {
+	if len(img.Widths) == 0 {
+		return &helper.FieldError{Message: "不能为空", Field: "images.widths"}
+	}
+
+	for index, w := range img.Widths {
+		if w <= 0 {
+			return &helper.FieldError{Message: "必须大于 0", Field: "images.widths[" + strconv.Itoa(index) + "]"}
+		}
+	}
+	sort.Ints(img.Widths)
+
+	if img.Quality == 0 {
+		img.Quality = 85
+	} else if img.Quality < 0 || img.Quality > 100 {
+		return &helper.FieldError{Message: "介于[1,100]之间的整数", Field: "images.quality"}
+	}
+
+	if img.MaxPixels == 0 {
+		img.MaxPixels = defaultImageMaxPixels
+	} else if img.MaxPixels < 0 {
+		return &helper.FieldError{Message: "必须大于 0", Field: "images.maxPixels"}
+	}
+
+	return nil
+}
+
+func (a *Archive) sanitize(tag l.Tag) *helper.FieldError {
 	if len(a.Type) == 0 {
 		a.Type = ArchiveTypeYear
 	} else {
@@ -145,6 +349,22 @@ func (a *Archive) sanitize() *helper.FieldError {
 		}
 	}
 
+	if len(a.YearFormat) == 0 {
+		a.YearFormat = locale.Sprintf(tag, archiveYearFormat)
+	}
+
+	if len(a.MonthFormat) == 0 {
+		a.MonthFormat = locale.Sprintf(tag, archiveMonthFormat)
+	}
+
+	if len(a.Format) == 0 {
+		if a.Type == ArchiveTypeMonth {
+			a.Format = a.MonthFormat
+		} else {
+			a.Format = a.YearFormat
+		}
+	}
+
 	return nil
 }
 
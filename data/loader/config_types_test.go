@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/issue9/assert"
+	l "golang.org/x/text/language"
 )
 
 func TestRSS_sanitize(t *testing.T) {
@@ -33,6 +34,20 @@ func TestRSS_sanitize(t *testing.T) {
 	rss.URL = "url"
 	a.NotError(rss.sanitize(conf, "rss"))
 	a.Equal(rss.Title, conf.Title)
+
+	// podcast
+	conf.Author = &Author{Name: "caixw", Email: "caixw@example.com"}
+	rss.Podcast = &Podcast{}
+	a.Error(rss.sanitize(conf, "rss"))
+	rss.Podcast.Image = "/podcast.png"
+	a.Error(rss.sanitize(conf, "rss"))
+	rss.Podcast.Category = "Technology"
+	a.NotError(rss.sanitize(conf, "rss"))
+	a.Equal(rss.Podcast.Author, "caixw").
+		Equal(rss.Podcast.Email, "caixw@example.com")
+
+	// 仅 rss 支持 podcast
+	a.Error(rss.sanitize(conf, "atom"))
 }
 
 func TestSitemapConfig_sanitize(t *testing.T) {
@@ -57,6 +72,92 @@ func TestSitemapConfig_sanitize(t *testing.T) {
 	a.Equal(s.Type, contentTypeXML) // 默认值
 }
 
+func TestImages_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	img := &Images{}
+	a.Error(img.sanitize())
+
+	img.Widths = []int{800, 0}
+	a.Error(img.sanitize())
+
+	img.Widths = []int{800, 400}
+	a.NotError(img.sanitize())
+	a.Equal(img.Widths, []int{400, 800}).
+		Equal(img.Quality, 85). // 默认值
+		Equal(img.MaxPixels, defaultImageMaxPixels)
+
+	img.Quality = 101
+	a.Error(img.sanitize())
+
+	img.Quality = 85
+	img.MaxPixels = -1
+	a.Error(img.sanitize())
+}
+
+func TestSanitize_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	s := &Sanitize{}
+	a.NotError(s.sanitize())
+
+	s.Hosts = []string{" WWW.youtube.com "}
+	s.Elements = []string{"IFRAME"}
+	a.NotError(s.sanitize())
+	a.Equal(s.Hosts, []string{"www.youtube.com"}).
+		Equal(s.Elements, []string{"iframe"})
+
+	s.Attributes = []string{""}
+	a.Error(s.sanitize())
+}
+
+func TestRelated_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	r := &Related{}
+	a.Error(r.sanitize())
+
+	// 默认值
+	r.Size = 5
+	a.NotError(r.sanitize())
+	a.Equal(*r.Series, 2)
+
+	// 0 表示不计算专题标签的权重，不会被默认值覆盖
+	series := 0.0
+	r.Series = &series
+	a.NotError(r.sanitize())
+	a.Equal(*r.Series, 0)
+
+	series = -1
+	a.Error(r.sanitize())
+
+	r = &Related{Size: 5, Text: -1}
+	a.Error(r.sanitize())
+}
+
+func TestArchive_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	archive := &Archive{}
+	a.NotError(archive.sanitize(l.SimplifiedChinese))
+	a.Equal(archive.Type, ArchiveTypeYear).
+		Equal(archive.Order, ArchiveOrderDesc).
+		Equal(archive.Format, "2006 年").
+		Equal(archive.YearFormat, "2006 年").
+		Equal(archive.MonthFormat, "2006 年 01 月")
+
+	archive = &Archive{Type: ArchiveTypeMonth}
+	a.NotError(archive.sanitize(l.English))
+	a.Equal(archive.Format, "January 2006")
+
+	archive = &Archive{Type: ArchiveTypeMonth, Format: "2006-01"}
+	a.NotError(archive.sanitize(l.English))
+	a.Equal(archive.Format, "2006-01")
+
+	archive = &Archive{Type: "day"}
+	a.Error(archive.sanitize(l.English))
+}
+
 func TestInString(t *testing.T) {
 	a := assert.New(t)
 
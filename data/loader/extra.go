@@ -0,0 +1,105 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"sort"
+
+	"github.com/caixw/gitype/helper"
+)
+
+// Extra 表示自定义的字段，可用于 config.yaml、tags.yaml 以及文章的 meta.yaml 中，
+// 内容会原样传递给模板，由主题决定如何使用，比如：
+//
+//	extra:
+//	  hideComments: true
+//	  heroColor: "#333"
+type Extra map[string]interface{}
+
+// 主题中 extra 可以声明的类型
+const (
+	ExtraTypeString = "string"
+	ExtraTypeBool   = "bool"
+	ExtraTypeInt    = "int"
+	ExtraTypeNumber = "number" // 整数或是浮点数
+	ExtraTypeList   = "list"
+	ExtraTypeMap    = "map"
+)
+
+// 主题中 extra 可以声明的对象
+const (
+	ExtraPost   = "post"
+	ExtraTag    = "tag"
+	ExtraConfig = "config"
+)
+
+// 检测主题中 extra 的声明，extra 的键名为 post、tag 或是 config，
+// 值为字段名与类型的对应关系。
+func sanitizeThemeExtra(extra map[string]map[string]string) *helper.FieldError {
+	for kind, fields := range extra {
+		if kind != ExtraPost && kind != ExtraTag && kind != ExtraConfig {
+			return &helper.FieldError{Message: "无效的值", Field: "extra." + kind}
+		}
+
+		for name, typ := range fields {
+			switch typ {
+			case ExtraTypeString, ExtraTypeBool, ExtraTypeInt, ExtraTypeNumber, ExtraTypeList, ExtraTypeMap:
+			default:
+				return &helper.FieldError{Message: "无效的值", Field: "extra." + kind + "." + name}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check 检测 extra 中的字段是否符合主题中声明的类型，
+// 未声明的字段不作检测，声明了但未指定的字段也不会报错，
+// 值为空的字段（比如 yaml 中的 `key:` 或 `key: null`）等同于未指定。
+//
+// kind 为 post、tag 或是 config。
+func (theme *Theme) Check(kind string, extra Extra) *helper.FieldError {
+	fields := theme.Extra[kind]
+	if len(fields) == 0 || len(extra) == 0 {
+		return nil
+	}
+
+	// 按字段名排序，保证每次返回的错误相同
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		typ, found := fields[name]
+		if !found || isExtraType(extra[name], typ) {
+			continue
+		}
+		return &helper.FieldError{Message: "类型必须为 %s", Field: "extra." + name, Args: []interface{}{typ}}
+	}
+
+	return nil
+}
+
+func isExtraType(val interface{}, typ string) bool {
+	switch val.(type) {
+	case nil: // 未指定值
+		return true
+	case string:
+		return typ == ExtraTypeString
+	case bool:
+		return typ == ExtraTypeBool
+	case int, int64, uint64:
+		return typ == ExtraTypeInt || typ == ExtraTypeNumber
+	case float64:
+		return typ == ExtraTypeNumber
+	case []interface{}:
+		return typ == ExtraTypeList
+	case map[interface{}]interface{}, map[string]interface{}:
+		return typ == ExtraTypeMap
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestSanitizeThemeExtra(t *testing.T) {
+	a := assert.New(t)
+
+	a.Nil(sanitizeThemeExtra(nil))
+	a.Nil(sanitizeThemeExtra(map[string]map[string]string{
+		ExtraPost:   {"hideComments": ExtraTypeBool},
+		ExtraConfig: {"twitter": ExtraTypeString},
+	}))
+
+	err := sanitizeThemeExtra(map[string]map[string]string{"page": {"x": ExtraTypeBool}})
+	a.NotNil(err).Equal(err.Field, "extra.page")
+
+	err = sanitizeThemeExtra(map[string]map[string]string{ExtraTag: {"x": "float"}})
+	a.NotNil(err).Equal(err.Field, "extra.tag.x")
+}
+
+func TestTheme_Check(t *testing.T) {
+	a := assert.New(t)
+
+	theme := &Theme{
+		Extra: map[string]map[string]string{
+			ExtraPost: {
+				"hideComments": ExtraTypeBool,
+				"weight":       ExtraTypeNumber,
+				"tags":         ExtraTypeList,
+			},
+		},
+	}
+
+	a.Nil(theme.Check(ExtraPost, nil))
+	a.Nil(theme.Check(ExtraTag, Extra{"hideComments": 5})) // 未声明的对象
+	a.Nil(theme.Check(ExtraPost, Extra{
+		"hideComments": true,
+		"weight":       1.5,
+		"tags":         []interface{}{"1"},
+		"other":        5, // 未声明的字段
+	}))
+	a.Nil(theme.Check(ExtraPost, Extra{"weight": 5}))
+
+	err := theme.Check(ExtraPost, Extra{"hideComments": "true", "weight": "x"})
+	a.NotNil(err).Equal(err.Field, "extra.hideComments")
+	// 值为空的字段等同于未指定
+	extra := Extra{}
+	a.NotError(yaml.Unmarshal([]byte("hideComments:\nweight: null\n"), &extra))
+	a.Equal(len(extra), 2)
+	a.Nil(theme.Check(ExtraPost, extra))
+}
+
+func TestIsExtraType(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(isExtraType("s", ExtraTypeString))
+	a.True(isExtraType(5, ExtraTypeInt))
+	a.True(isExtraType(5, ExtraTypeNumber))
+	a.False(isExtraType(5.1, ExtraTypeInt))
+	a.True(isExtraType(map[interface{}]interface{}{}, ExtraTypeMap))
+	a.True(isExtraType(nil, ExtraTypeString)) // 未指定值
+	a.True(isExtraType(nil, ExtraTypeMap))
+}
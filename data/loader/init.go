@@ -7,9 +7,7 @@ package loader
 import (
 	"fmt"
 	"os"
-	"path"
 	"path/filepath"
-	"strconv"
 	"time"
 
 	"github.com/caixw/gitype/helper"
@@ -21,7 +19,7 @@ import (
 var defaultRobots = `User-agent:*
 Disallow:/themes/`
 
-var defaultPostContent = `<section>about
+var defaultPageContent = `<section>about
 </section>`
 
 var defaultTheme = &Theme{
@@ -31,12 +29,10 @@ var defaultTheme = &Theme{
 	URL:         vars.URL,
 }
 
-var defaultPostMeta = &Post{
-	Title:    "about",
-	Tags:     "default",
-	Created:  time.Now(),
-	Modified: time.Now(),
-	State:    StateLast,
+var defaultPageMeta = &SinglePage{
+	Title:     "about",
+	Modified:  time.Now(),
+	Permalink: "/about.html",
 }
 
 var defaultConfig = &Config{
@@ -56,6 +52,10 @@ var defaultConfig = &Config{
 		URL:   "https://creativecommons.org/licenses/by/4.0/deed.zh",
 	},
 
+	Menus: []*Link{
+		{Page: "about"},
+	},
+
 	Theme:  "default",
 	Uptime: time.Now(),
 	Archive: &Archive{
@@ -63,6 +63,10 @@ var defaultConfig = &Config{
 		Format: "2006 年",
 	},
 
+	Related: &Related{
+		Size: 5,
+	},
+
 	RSS: &RSS{
 		Title: "RSS",
 		URL:   "/rss.xml",
@@ -117,6 +121,10 @@ func Init(path *p.Path) error {
 		return err
 	}
 
+	if err := initPages(path); err != nil {
+		return err
+	}
+
 	return initThemes(path)
 }
 
@@ -154,23 +162,31 @@ func initRaws(path *p.Path) error {
 	return helper.DumpTextFile(path.RawsPath("robots.txt"), defaultRobots)
 }
 
-// 初始化 data/posts 目录下数据
+// 初始化 data/posts 目录
 func initPosts(p *p.Path) error {
-	slug := path.Join(strconv.Itoa(time.Now().Year()), "about")
+	if !utils.FileExists(p.PostsDir) {
+		return os.Mkdir(p.PostsDir, os.ModePerm)
+	}
+	return nil
+}
+
+// 初始化 data/pages 目录下的数据，about 页面以独立页面的形式存在，不会出现在文章列表中。
+func initPages(p *p.Path) error {
+	slug := "about"
 
-	dir := filepath.Join(p.PostsDir, slug)
+	dir := filepath.Join(p.PagesDir, slug)
 	if !utils.FileExists(dir) {
 		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 			return err
 		}
 	}
 
-	if err := helper.DumpYAMLFile(p.PostMetaPath(slug), defaultPostMeta); err != nil {
+	if err := helper.DumpYAMLFile(p.PageMetaPath(slug), defaultPageMeta); err != nil {
 		return err
 	}
 
 	// content.html
-	return helper.DumpTextFile(p.PostContentPath(slug), defaultPostContent)
+	return helper.DumpTextFile(p.PageContentPath(slug), defaultPageContent)
 }
 
 // 初始化 data/themes 目录
@@ -0,0 +1,107 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"regexp"
+	"strconv"
+
+	l "golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+)
+
+// 语言标签同时也作为地址的前缀，只能包含字母、数字和 -
+var languagePattern = regexp.MustCompile("^[a-zA-Z0-9-]+$")
+
+// Language 网站的其它语言版本
+//
+// 该语言下所有页面的地址都以 Tag 作为前缀，比如 /en/posts/1.html，
+// 文章、标签、归档以及各类 feed 等也都会针对该语言单独生成。
+type Language struct {
+	Tag      string `yaml:"tag"`                // 语言标签，比如 en
+	Name     string `yaml:"name,omitempty"`     // 语言的名称，用于切换语言，默认为该语言对自身的称呼，比如 English
+	Title    string `yaml:"title,omitempty"`    // 该语言下的网站名称，默认与 title 相同
+	Subtitle string `yaml:"subtitle,omitempty"` // 该语言下的网站副标题，默认与 subtitle 相同
+
+	LanguageTag l.Tag `yaml:"-"`
+}
+
+// LoadLanguageConfig 加载 lang 语言版本的配置信息
+//
+// 在 LoadConfig 的基础上，以 lang 中的值替换语言、网站名称和副标题，
+// 且所有的地址都以 lang.Tag 作为前缀。lang 应该为 LoadConfig 返回的 Config.Languages 中的元素。
+func LoadLanguageConfig(path *path.Path, url string, lang *Language) (*Config, error) {
+	return loadConfig(path, url, lang)
+}
+
+func (lang *Language) sanitize() *helper.FieldError {
+	if !languagePattern.MatchString(lang.Tag) {
+		return &helper.FieldError{Message: "只能包含字母、数字和 -", Field: "tag"}
+	}
+
+	tag, err := l.Parse(lang.Tag)
+	if err != nil {
+		return &helper.FieldError{Message: err.Error(), Field: "tag"}
+	}
+	lang.LanguageTag = tag
+
+	if lang.Name == "" {
+		lang.Name = languageName(tag)
+	}
+
+	return nil
+}
+
+// 将 lang 中的内容应用到 conf
+func (lang *Language) apply(conf *Config) {
+	conf.Language = lang.Tag
+	conf.LanguageName = lang.Name
+	if lang.Title != "" {
+		conf.Title = lang.Title
+	}
+	if lang.Subtitle != "" {
+		conf.Subtitle = lang.Subtitle
+	}
+
+	// 其它语言版本只由默认语言的配置维护
+	conf.Languages = nil
+}
+
+func (conf *Config) sanitizeLanguages() *helper.FieldError {
+	if conf.LanguageName == "" {
+		conf.LanguageName = languageName(conf.LanguageTag)
+	}
+
+	tags := make([]l.Tag, 0, len(conf.Languages)+1)
+	tags = append(tags, conf.LanguageTag)
+	for index, lang := range conf.Languages {
+		field := "languages[" + strconv.Itoa(index) + "]."
+
+		if err := lang.sanitize(); err != nil {
+			err.Field = field + err.Field
+			return err
+		}
+
+		for _, tag := range tags {
+			if tag == lang.LanguageTag {
+				return &helper.FieldError{Message: "重复的语言", Field: field + "tag"}
+			}
+		}
+		tags = append(tags, lang.LanguageTag)
+	}
+
+	return nil
+}
+
+// 获取语言对自身的称呼，比如 English、中文等，找不到时返回语言标签本身
+func languageName(tag l.Tag) string {
+	if name := display.Self.Name(tag); name != "" {
+		return name
+	}
+	return tag.String()
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+	l "golang.org/x/text/language"
+)
+
+func TestConfig_sanitizeLanguages(t *testing.T) {
+	a := assert.New(t)
+
+	conf := &Config{
+		LanguageTag: l.MustParse("zh-Hans"),
+		Languages: []*Language{
+			{Tag: "en"},
+			{Tag: "ja", Name: "日本"},
+		},
+	}
+	a.NotError(conf.sanitizeLanguages())
+	a.Equal(conf.LanguageName, "简体中文")
+	a.Equal(conf.Languages[0].Name, "English").
+		Equal(conf.Languages[0].LanguageTag, l.English)
+	a.Equal(conf.Languages[1].Name, "日本")
+
+	// 与默认语言相同
+	conf.Languages = append(conf.Languages, &Language{Tag: "zh-Hans"})
+	err := conf.sanitizeLanguages()
+	a.Error(err).Equal(err.Field, "languages[2].tag")
+
+	// 无效的字符
+	conf.Languages = []*Language{{Tag: "en/us"}}
+	err = conf.sanitizeLanguages()
+	a.Error(err).Equal(err.Field, "languages[0].tag")
+}
+
+func TestLanguage_apply(t *testing.T) {
+	a := assert.New(t)
+
+	conf := &Config{
+		Title:     "title",
+		Subtitle:  "subtitle",
+		Language:  "zh-Hans",
+		Languages: []*Language{{Tag: "en"}},
+	}
+	lang := &Language{Tag: "en", Name: "English", Title: "english title"}
+	lang.apply(conf)
+	a.Equal(conf.Language, "en").
+		Equal(conf.LanguageName, "English").
+		Equal(conf.Title, "english title").
+		Equal(conf.Subtitle, "subtitle").
+		Empty(conf.Languages)
+}
@@ -4,15 +4,21 @@
 
 package loader
 
-import "github.com/caixw/gitype/vars"
+import (
+	"github.com/caixw/gitype/locale"
+	"github.com/caixw/gitype/vars"
+)
 
-// 默认标题的定义
+// 默认标题的定义，会根据 config.yaml 中的 language 进行翻译。
 const (
 	tagTitle      = "标签：" + vars.ContentPlaceholder
 	tagsTitle     = "标签"
 	archivesTitle = "归档"
+	archiveTitle  = "归档：" + vars.ContentPlaceholder
 	searchTitle   = "搜索：" + vars.ContentPlaceholder
 	linksTitle    = "友情链接"
+	authorTitle   = "作者：" + vars.ContentPlaceholder
+	authorsTitle  = "作者"
 	postTitle     = vars.ContentPlaceholder
 )
 
@@ -38,6 +44,9 @@ func (conf *Config) initPages() {
 	if ps[vars.PageArchives] == nil {
 		ps[vars.PageArchives] = &Page{}
 	}
+	if ps[vars.PageArchive] == nil {
+		ps[vars.PageArchive] = &Page{}
+	}
 	if ps[vars.PageSearch] == nil {
 		ps[vars.PageSearch] = &Page{}
 	}
@@ -50,34 +59,59 @@ func (conf *Config) initPages() {
 	if ps[vars.PagePost] == nil {
 		ps[vars.PagePost] = &Page{}
 	}
+	if ps[vars.PageSingle] == nil {
+		ps[vars.PageSingle] = &Page{}
+	}
 	if ps[vars.PagePosts] == nil {
 		ps[vars.PagePosts] = &Page{}
 	}
+	if ps[vars.PageAuthor] == nil {
+		ps[vars.PageAuthor] = &Page{}
+	}
+	if ps[vars.PageAuthors] == nil {
+		ps[vars.PageAuthors] = &Page{}
+	}
 
 	if len(ps[vars.PageTag].Title) == 0 {
-		ps[vars.PageTag].Title = tagTitle
+		ps[vars.PageTag].Title = locale.Sprintf(conf.LanguageTag, tagTitle)
 	}
 
 	if len(ps[vars.PageTags].Title) == 0 {
-		ps[vars.PageTags].Title = tagsTitle
+		ps[vars.PageTags].Title = locale.Sprintf(conf.LanguageTag, tagsTitle)
 	}
 
 	if len(ps[vars.PageArchives].Title) == 0 {
-		ps[vars.PageArchives].Title = archivesTitle
+		ps[vars.PageArchives].Title = locale.Sprintf(conf.LanguageTag, archivesTitle)
+	}
+
+	if len(ps[vars.PageArchive].Title) == 0 {
+		ps[vars.PageArchive].Title = locale.Sprintf(conf.LanguageTag, archiveTitle)
 	}
 
 	if len(ps[vars.PageSearch].Title) == 0 {
-		ps[vars.PageSearch].Title = searchTitle
+		ps[vars.PageSearch].Title = locale.Sprintf(conf.LanguageTag, searchTitle)
 	}
 
 	if len(ps[vars.PageLinks].Title) == 0 {
-		ps[vars.PageLinks].Title = linksTitle
+		ps[vars.PageLinks].Title = locale.Sprintf(conf.LanguageTag, linksTitle)
+	}
+
+	if len(ps[vars.PageAuthor].Title) == 0 {
+		ps[vars.PageAuthor].Title = locale.Sprintf(conf.LanguageTag, authorTitle)
+	}
+
+	if len(ps[vars.PageAuthors].Title) == 0 {
+		ps[vars.PageAuthors].Title = locale.Sprintf(conf.LanguageTag, authorsTitle)
 	}
 
 	if len(ps[vars.PagePost].Title) == 0 {
 		ps[vars.PagePost].Title = postTitle
 	}
 
+	if len(ps[vars.PageSingle].Title) == 0 {
+		ps[vars.PageSingle].Title = postTitle
+	}
+
 	suffix := conf.TitleSeparator + conf.Title
 	for _, page := range conf.Pages {
 		if len(page.Title) == 0 { // 没有内容，则直接使用网站标
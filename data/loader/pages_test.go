@@ -0,0 +1,39 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+	l "golang.org/x/text/language"
+
+	"github.com/caixw/gitype/vars"
+)
+
+func TestConfig_initPages(t *testing.T) {
+	a := assert.New(t)
+
+	// 页面的默认标题根据语言翻译
+	conf := &Config{Title: "title", TitleSeparator: " | ", LanguageTag: l.MustParse("zh-Hans")}
+	conf.initPages()
+	a.Equal(conf.Pages[vars.PageTags].Title, "标签 | title").
+		Equal(conf.Pages[vars.PageTag].Title, "标签："+vars.ContentPlaceholder+" | title")
+	a.Equal(conf.Pages[vars.PageIndex], conf.Pages[vars.PagePosts])
+
+	conf = &Config{Title: "title-en", TitleSeparator: " | ", LanguageTag: l.English}
+	conf.initPages()
+	a.Equal(conf.Pages[vars.PageTags].Title, "Tags | title-en")
+
+	// 已经指定的标题不会被翻译
+	conf = &Config{
+		Title:          "title-en",
+		TitleSeparator: " | ",
+		LanguageTag:    l.English,
+		Pages:          map[string]*Page{vars.PageTags: {Title: "标签"}},
+	}
+	conf.initPages()
+	a.Equal(conf.Pages[vars.PageTags].Title, "标签 | title-en")
+}
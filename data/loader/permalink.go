@@ -0,0 +1,415 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/vars"
+)
+
+// 地址格式中可用的占位符
+const (
+	permalinkSlug  = ":slug"
+	permalinkYear  = ":year"
+	permalinkMonth = ":month"
+	permalinkDay   = ":day"
+	permalinkPage  = ":page"
+)
+
+var (
+	permalinkPlaceholder = regexp.MustCompile(":[a-z]+")
+	pageQueryPattern     = regexp.MustCompile(`^\?\w+=` + permalinkPage + "$")
+)
+
+// Permalinks 各类页面地址的格式
+//
+// 格式中可以使用以下占位符：
+//   - :slug 文章、标签、作者或独立页面的唯一名称，仅 post、tag、author、authorFeed 和 single 可用；
+//   - :year、:month 和 :day 文章创建时间中的年、月和日，仅 post 可用；
+//     archiveYear 和 archiveMonth 也可以使用 :year 和 :month，表示存档的年份和月份；
+//   - :page 页码，仅 page 可用。
+//
+// 上线之后请谨慎修改这些值，可能会让已经分享出去的链接变为无效链接，
+// 必要时可以通过 redirects.yaml 或是文章的 aliases 将旧的地址跳转到新的地址。
+//
+// 网站部署在子目录下时，所有生成的地址都会带上该子目录，格式中则不需要包含。
+type Permalinks struct {
+	Post     string `yaml:"post,omitempty"`     // 文章详细页，默认为 /posts/:slug.html
+	Tag      string `yaml:"tag,omitempty"`      // 标签详细页，默认为 /tags/:slug.html
+	Index    string `yaml:"index,omitempty"`    // 文章列表页，默认为 /index.html
+	Tags     string `yaml:"tags,omitempty"`     // 标签列表页，默认为 /tags.html
+	Links    string `yaml:"links,omitempty"`    // 友情链接页，默认为 /links.html
+	Archives string `yaml:"archives,omitempty"` // 归档页，默认为 /archives.html
+	Search   string `yaml:"search,omitempty"`   // 搜索页，默认为 /search.html
+	Author   string `yaml:"author,omitempty"`   // 作者详细页，默认为 /authors/:slug.html，以路径的形式分页时为 /authors/:slug/
+	Authors  string `yaml:"authors,omitempty"`  // 作者列表页，默认为 /authors.html
+
+	// 作者的 atom，默认为 /authors/:slug.xml，仅在配置了 atom 时有效
+	AuthorFeed string `yaml:"authorFeed,omitempty"`
+
+	// 按年和按月的存档页，默认为 /archives/:year.html 和 /archives/:year/:month.html，
+	// 以路径的形式分页时为 /archives/:year/ 和 /archives/:year/:month/
+	ArchiveYear  string `yaml:"archiveYear,omitempty"`
+	ArchiveMonth string `yaml:"archiveMonth,omitempty"`
+
+	// pages 目录下的独立页面，默认为 /pages/:slug.html，页面的 meta.yaml 中可以单独指定地址
+	Single string `yaml:"single,omitempty"`
+
+	// 分页的格式，默认为 ?page=:page。
+	//
+	// 以 ? 开头表示以查询参数的形式附加在列表页的地址之后，比如 /index.html?page=2；
+	// 否则以路径的形式附加在列表页的地址之后，比如 page/:page/ 会生成 /tags/go/page/2/，
+	// 此时 index、tag 和 search 都必须以 / 结尾。
+	Page string `yaml:"page,omitempty"`
+
+	url  string // 网站的地址，比如 https://example.com/blog，不以 / 结尾
+	root string // 网站所在的子目录，比如 /blog，在根目录时为空
+}
+
+// siteURL 为网站的地址，若包含路径部分，则表示网站部署在该子目录下。
+func (p *Permalinks) sanitize(siteURL string) *helper.FieldError {
+	p.url = strings.TrimSuffix(siteURL, "/")
+	if u, err := url.Parse(p.url); err == nil {
+		p.root = strings.TrimSuffix(u.Path, "/")
+	}
+
+	// 作者详细页和存档页是后来加入的，为了不影响以路径的形式分页的已有配置，默认值需要以 / 结尾
+	author := "/authors/" + permalinkSlug + ".html"
+	archiveYear := "/archives/" + permalinkYear + ".html"
+	archiveMonth := "/archives/" + permalinkYear + "/" + permalinkMonth + ".html"
+	if p.Page != "" && p.Page[0] != '?' {
+		author = "/authors/" + permalinkSlug + "/"
+		archiveYear = "/archives/" + permalinkYear + "/"
+		archiveMonth = "/archives/" + permalinkYear + "/" + permalinkMonth + "/"
+	}
+
+	defaults := []struct {
+		val *string
+		def string
+	}{
+		{&p.Post, "/posts/" + permalinkSlug + ".html"},
+		{&p.Tag, "/tags/" + permalinkSlug + ".html"},
+		{&p.Index, "/index.html"},
+		{&p.Tags, "/tags.html"},
+		{&p.Links, "/links.html"},
+		{&p.Archives, "/archives.html"},
+		{&p.Search, "/search.html"},
+		{&p.Author, author},
+		{&p.Authors, "/authors.html"},
+		{&p.AuthorFeed, "/authors/" + permalinkSlug + ".xml"},
+		{&p.ArchiveYear, archiveYear},
+		{&p.ArchiveMonth, archiveMonth},
+		{&p.Single, "/pages/" + permalinkSlug + ".html"},
+		{&p.Page, "?" + vars.URLQueryPage + "=" + permalinkPage},
+	}
+	for _, item := range defaults {
+		if *item.val == "" {
+			*item.val = item.def
+		}
+	}
+
+	patterns := []struct {
+		field        string
+		val          string
+		placeholders []string // 可用的占位符，第一个为必须包含的占位符
+	}{
+		{"post", p.Post, []string{permalinkSlug, permalinkYear, permalinkMonth, permalinkDay}},
+		{"tag", p.Tag, []string{permalinkSlug}},
+		{"index", p.Index, nil},
+		{"tags", p.Tags, nil},
+		{"links", p.Links, nil},
+		{"archives", p.Archives, nil},
+		{"search", p.Search, nil},
+		{"author", p.Author, []string{permalinkSlug}},
+		{"authors", p.Authors, nil},
+		{"authorFeed", p.AuthorFeed, []string{permalinkSlug}},
+		{"archiveYear", p.ArchiveYear, []string{permalinkYear}},
+		{"archiveMonth", p.ArchiveMonth, []string{permalinkMonth, permalinkYear}},
+		{"single", p.Single, []string{permalinkSlug}},
+	}
+
+	// 占位符替换为 * 之后的值，用于判断格式之间是否冲突
+	shapes := make(map[string]string, len(patterns))
+	for _, item := range patterns {
+		field := "permalinks." + item.field
+
+		if item.val[0] != '/' {
+			return &helper.FieldError{Message: "必须以 / 开头", Field: field}
+		}
+
+		if strings.ContainsAny(item.val, "{}?#") {
+			return &helper.FieldError{Message: "不能包含 {、}、? 和 # 等字符", Field: field}
+		}
+
+		if err := checkPlaceholders(item.val, item.placeholders); err != nil {
+			return helper.NewFieldError("", field, err)
+		}
+
+		// 除了第一个占位符，按月的存档页还必须包含年份
+		if item.field == "archiveMonth" && !strings.Contains(item.val, permalinkYear) {
+			return &helper.FieldError{Message: "必须包含占位符 %s", Field: field, Args: []interface{}{permalinkYear}}
+		}
+
+		shape := permalinkPlaceholder.ReplaceAllString(item.val, "*")
+		if name, found := shapes[shape]; found {
+			return &helper.FieldError{Message: "与 permalinks.%s 冲突", Field: field, Args: []interface{}{name}}
+		}
+		shapes[shape] = item.field
+	}
+
+	if err := checkPlaceholders(p.Page, []string{permalinkPage}); err != nil {
+		return helper.NewFieldError("", "permalinks.page", err)
+	}
+
+	if p.Page[0] == '?' {
+		if !pageQueryPattern.MatchString(p.Page) {
+			return &helper.FieldError{Message: "以查询参数的形式分页时，格式必须为 ?key=%s", Field: "permalinks.page", Args: []interface{}{permalinkPage}}
+		}
+		return nil
+	}
+
+	if p.Page[0] == '/' {
+		return &helper.FieldError{Message: "不能以 / 开头", Field: "permalinks.page"}
+	}
+
+	if strings.ContainsAny(p.Page, "{}?#") {
+		return &helper.FieldError{Message: "不能包含 {、}、? 和 # 等字符", Field: "permalinks.page"}
+	}
+
+	lists := []struct{ field, val string }{
+		{"index", p.Index},
+		{"tag", p.Tag},
+		{"search", p.Search},
+		{"author", p.Author},
+		{"archiveYear", p.ArchiveYear},
+		{"archiveMonth", p.ArchiveMonth},
+	}
+	for _, item := range lists {
+		if !strings.HasSuffix(item.val, "/") {
+			return &helper.FieldError{Message: "以路径的形式分页时，必须以 / 结尾", Field: "permalinks." + item.field}
+		}
+	}
+
+	return nil
+}
+
+// 检测 pattern 中的占位符是否都在 placeholders 中，且包含了 placeholders[0]
+func checkPlaceholders(pattern string, placeholders []string) *helper.Error {
+	for _, ph := range permalinkPlaceholder.FindAllString(pattern, -1) {
+		if !inStrings(ph, placeholders) {
+			return helper.Errorf("不支持的占位符 %s", ph)
+		}
+	}
+
+	if len(placeholders) > 0 && !strings.Contains(pattern, placeholders[0]) {
+		return helper.Errorf("必须包含占位符 %s", placeholders[0])
+	}
+
+	return nil
+}
+
+// PostURL 构建文章的地址
+func (p *Permalinks) PostURL(slug string, created time.Time) string {
+	return strings.NewReplacer(
+		permalinkSlug, strings.Trim(slug, "/"),
+		permalinkYear, created.Format("2006"),
+		permalinkMonth, created.Format("01"),
+		permalinkDay, created.Format("02"),
+	).Replace(p.root + p.Post)
+}
+
+// PostsURL 构建文章列表的地址
+//
+// 首页返回 /，其它页面返回 IndexURL(page) 的值。
+func (p *Permalinks) PostsURL(page int) string {
+	if page <= 1 {
+		return p.root + "/"
+	}
+	return p.IndexURL(page)
+}
+
+// IndexURL 构建文章列表页的地址
+func (p *Permalinks) IndexURL(page int) string {
+	return p.pageURL(p.root+p.Index, page)
+}
+
+// TagURL 构建标签的地址
+func (p *Permalinks) TagURL(slug string, page int) string {
+	url := strings.Replace(p.root+p.Tag, permalinkSlug, strings.Trim(slug, "/"), -1)
+	return p.pageURL(url, page)
+}
+
+// TagsURL 构建标签列表的地址
+func (p *Permalinks) TagsURL() string {
+	return p.root + p.Tags
+}
+
+// LinksURL 构建友情链接的地址
+func (p *Permalinks) LinksURL() string {
+	return p.root + p.Links
+}
+
+// ArchivesURL 构建归档页面的地址
+func (p *Permalinks) ArchivesURL() string {
+	return p.root + p.Archives
+}
+
+// ArchiveYearURL 构建按年存档页的地址
+func (p *Permalinks) ArchiveYearURL(year, page int) string {
+	url := strings.Replace(p.root+p.ArchiveYear, permalinkYear, strconv.Itoa(year), -1)
+	return p.pageURL(url, page)
+}
+
+// ArchiveMonthURL 构建按月存档页的地址，月份始终为两位数字
+func (p *Permalinks) ArchiveMonthURL(year, month, page int) string {
+	url := strings.NewReplacer(
+		permalinkYear, strconv.Itoa(year),
+		permalinkMonth, fmt.Sprintf("%02d", month),
+	).Replace(p.root + p.ArchiveMonth)
+	return p.pageURL(url, page)
+}
+
+// SinglePageURL 构建独立页面的地址
+func (p *Permalinks) SinglePageURL(slug string) string {
+	return strings.Replace(p.root+p.Single, permalinkSlug, strings.Trim(slug, "/"), -1)
+}
+
+// AuthorURL 构建作者详细页的地址
+func (p *Permalinks) AuthorURL(id string, page int) string {
+	url := strings.Replace(p.root+p.Author, permalinkSlug, id, -1)
+	return p.pageURL(url, page)
+}
+
+// AuthorsURL 构建作者列表页的地址
+func (p *Permalinks) AuthorsURL() string {
+	return p.root + p.Authors
+}
+
+// AuthorFeedURL 构建作者的 atom 地址
+func (p *Permalinks) AuthorFeedURL(id string) string {
+	return strings.Replace(p.root+p.AuthorFeed, permalinkSlug, id, -1)
+}
+
+// SearchURL 构建搜索页面的地址，q 会被转义。
+func (p *Permalinks) SearchURL(q string, page int) string {
+	u := p.root + p.Search
+
+	query := url.Values{}
+	if len(q) > 0 {
+		query.Set(vars.URLQuerySearch, q)
+	}
+
+	if page > 1 {
+		if p.Page[0] != '?' {
+			u += p.paging(page)
+		} else {
+			query.Set(p.PageQuery(), strconv.Itoa(page))
+		}
+	}
+
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// SearchTemplateURL 构建以 placeholder 作为搜索内容的搜索页地址，
+// placeholder 不会被转义，用于 opensearch 等需要保留占位符的场合，
+// 比如 {searchTerms}。
+func (p *Permalinks) SearchTemplateURL(placeholder string) string {
+	return p.root + p.Search + "?" + vars.URLQuerySearch + "=" + placeholder
+}
+
+// ThemeURL 构建主题文件的地址
+func (p *Permalinks) ThemeURL(path string) string {
+	return p.root + vars.ThemeURL(path)
+}
+
+// AssetURL 构建文章资源的地址
+func (p *Permalinks) AssetURL(path string) string {
+	return p.root + vars.AssetURL(path)
+}
+
+// ImageURL 构建程序生成的图片的地址
+func (p *Permalinks) ImageURL(path string) string {
+	return p.root + vars.ImageURL(path)
+}
+
+// URL 为以 / 开头的站内地址加上网站所在的子目录，其它地址原样返回。
+//
+// 用于处理配置文件中指定的地址，比如 rss.url 和文章的 image 等。
+func (p *Permalinks) URL(path string) string {
+	if path == "" || path[0] != '/' || strings.HasPrefix(path, "//") {
+		return path
+	}
+	return p.root + path
+}
+
+// Root 网站所在的子目录，比如 /blog，在根目录时为空。
+func (p *Permalinks) Root() string {
+	return p.root
+}
+
+// SiteURL 网站的地址，若网站部署在子目录下，则包含该子目录。
+func (p *Permalinks) SiteURL() string {
+	return p.url
+}
+
+// AbsURL 将 path 转换成带域名的地址，若已经是完整的地址，则原样返回。
+//
+// 以 / 开头的 path 应该已经包含了网站所在的子目录，
+// 其它的则被当作相对于网站地址的路径。
+func (p *Permalinks) AbsURL(path string) string {
+	switch {
+	case strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://"):
+		return path
+	case path == "":
+		return p.url
+	case path[0] == '/':
+		return strings.TrimSuffix(p.url, p.root) + path
+	default:
+		return p.url + "/" + path
+	}
+}
+
+// PageRoute 获取以路径的形式分页时，列表页 url 的分页路由项，
+// 页码的参数名为 page，以查询参数的形式分页时，返回空值。
+func (p *Permalinks) PageRoute(url string) string {
+	if p.Page[0] == '?' {
+		return ""
+	}
+	return url + strings.Replace(p.Page, permalinkPage, "{"+vars.URLQueryPage+"}", -1)
+}
+
+// PageQuery 获取以查询参数的形式分页时，页码的参数名，以路径的形式分页时，返回空值。
+func (p *Permalinks) PageQuery() string {
+	if p.Page[0] != '?' {
+		return ""
+	}
+
+	query := p.Page[1:]
+	if index := strings.IndexByte(query, '='); index >= 0 {
+		return query[:index]
+	}
+	return query
+}
+
+func (p *Permalinks) pageURL(url string, page int) string {
+	if page <= 1 {
+		return url
+	}
+	return url + p.paging(page)
+}
+
+func (p *Permalinks) paging(page int) string {
+	return strings.Replace(p.Page, permalinkPage, strconv.Itoa(page), -1)
+}
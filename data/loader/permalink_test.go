@@ -0,0 +1,227 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/vars"
+)
+
+func TestPermalinks_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	p := &Permalinks{}
+	a.Nil(p.sanitize(""))
+	a.Equal(p.Post, "/posts/:slug.html").
+		Equal(p.Tag, "/tags/:slug.html").
+		Equal(p.Index, "/index.html").
+		Equal(p.Page, "?page=:page")
+
+	p = &Permalinks{Post: "/:year/:month/:slug/", Tag: "/tags/:slug/", Index: "/", Search: "/search/", Page: "page/:page/"}
+	a.Nil(p.sanitize(""))
+
+	// 不以 / 开头
+	p = &Permalinks{Post: "posts/:slug.html"}
+	a.Equal(p.sanitize("").Field, "permalinks.post")
+
+	// 缺少 :slug
+	p = &Permalinks{Post: "/posts/:year.html"}
+	a.Equal(p.sanitize("").Field, "permalinks.post")
+
+	// 不支持的占位符
+	p = &Permalinks{Tag: "/tags/:year/:slug.html"}
+	a.Equal(p.sanitize("").Field, "permalinks.tag")
+
+	// 静态页面不能包含占位符
+	p = &Permalinks{Links: "/links/:slug.html"}
+	a.Equal(p.sanitize("").Field, "permalinks.links")
+
+	// 与 post 冲突
+	p = &Permalinks{Post: "/:slug/", Tag: "/:slug/"}
+	a.Equal(p.sanitize("").Field, "permalinks.tag")
+
+	// 相同的地址
+	p = &Permalinks{Tags: "/tags.html", Links: "/tags.html"}
+	a.Equal(p.sanitize("").Field, "permalinks.links")
+
+	// 作者页缺少 :slug
+	p = &Permalinks{Author: "/authors/caixw.html"}
+	a.Equal(p.sanitize("").Field, "permalinks.author")
+
+	// 按月的存档页缺少 :year
+	p = &Permalinks{ArchiveMonth: "/archives/:month.html"}
+	a.Equal(p.sanitize("").Field, "permalinks.archiveMonth")
+
+	// 缺少 :page
+	p = &Permalinks{Page: "?page=1"}
+	a.Equal(p.sanitize("").Field, "permalinks.page")
+
+	// 格式错误的查询参数
+	p = &Permalinks{Page: "?page=:page&x=1"}
+	a.Equal(p.sanitize("").Field, "permalinks.page")
+
+	// 以路径的形式分页时，列表页必须以 / 结尾
+	p = &Permalinks{Page: "page/:page/"}
+	a.Equal(p.sanitize("").Field, "permalinks.index")
+}
+
+func TestPermalinks_PostURL(t *testing.T) {
+	a := assert.New(t)
+	p := &Permalinks{}
+	a.Nil(p.sanitize(""))
+
+	a.Equal(p.PostURL("1", time.Now()), "/posts/1.html")
+}
+
+func TestPermalinks_PostsURL(t *testing.T) {
+	a := assert.New(t)
+	p := &Permalinks{}
+	a.Nil(p.sanitize(""))
+
+	a.Equal(p.PostsURL(0), "/")
+	a.Equal(p.PostsURL(1), "/")
+	a.Equal(p.PostsURL(2), "/index.html?"+vars.URLQueryPage+"=2")
+}
+
+func TestPermalinks_TagURL(t *testing.T) {
+	a := assert.New(t)
+	p := &Permalinks{}
+	a.Nil(p.sanitize(""))
+
+	a.Equal(p.TagURL("1", 0), "/tags/1.html")
+	a.Equal(p.TagURL("1", 1), "/tags/1.html")
+	a.Equal(p.TagURL("1", 2), "/tags/1.html?"+vars.URLQueryPage+"=2")
+}
+
+func TestPermalinks_SearchURL(t *testing.T) {
+	a := assert.New(t)
+	p := &Permalinks{}
+	a.Nil(p.sanitize(""))
+
+	a.Equal(p.SearchURL("", 0), "/search.html")
+	a.Equal(p.SearchURL("", 1), "/search.html")
+	a.Equal(p.SearchURL("", 2), "/search.html?"+vars.URLQueryPage+"=2")
+
+	a.Equal(p.SearchURL("q", 0), "/search.html?"+vars.URLQuerySearch+"=q")
+	a.Equal(p.SearchURL("q", 1), "/search.html?"+vars.URLQuerySearch+"=q")
+	a.Equal(p.SearchURL("q", 2), "/search.html?"+vars.URLQueryPage+"=2&"+vars.URLQuerySearch+"=q")
+
+	// 转义
+	a.Equal(p.SearchURL("a&page=3", 0), "/search.html?"+vars.URLQuerySearch+"=a%26page%3D3")
+	a.Equal(p.SearchURL("<script>", 0), "/search.html?"+vars.URLQuerySearch+"=%3Cscript%3E")
+}
+
+// 所有默认的地址都以 .html 结尾
+func TestPermalinks_suffix(t *testing.T) {
+	a := assert.New(t)
+	p := &Permalinks{}
+	a.Nil(p.sanitize(""))
+
+	a.True(strings.HasSuffix(p.PostURL("1", time.Now()), ".html"))
+	a.True(strings.HasSuffix(p.TagURL("1", 1), ".html"))
+	a.True(strings.HasSuffix(p.IndexURL(1), ".html"))
+	a.True(strings.HasSuffix(p.TagsURL(), ".html"))
+	a.True(strings.HasSuffix(p.LinksURL(), ".html"))
+	a.True(strings.HasSuffix(p.ArchivesURL(), ".html"))
+	a.True(strings.HasSuffix(p.SearchURL("", 1), ".html"))
+}
+
+func TestPermalinks_URL(t *testing.T) {
+	a := assert.New(t)
+	created := time.Date(2018, 7, 3, 0, 0, 0, 0, time.UTC)
+
+	p := &Permalinks{}
+	a.Nil(p.sanitize(""))
+
+	a.Equal(p.PostURL("1", created), "/posts/1.html")
+	a.Equal(p.PostURL("/folder/1", created), "/posts/folder/1.html")
+
+	a.Equal(p.PostsURL(0), "/")
+	a.Equal(p.PostsURL(1), "/")
+	a.Equal(p.PostsURL(2), "/index.html?"+vars.URLQueryPage+"=2")
+	a.Equal(p.IndexURL(1), "/index.html")
+
+	a.Equal(p.TagURL("1", 0), "/tags/1.html")
+	a.Equal(p.TagURL("1", 1), "/tags/1.html")
+	a.Equal(p.TagURL("1", 2), "/tags/1.html?"+vars.URLQueryPage+"=2")
+
+	a.Equal(p.AuthorURL("caixw", 1), "/authors/caixw.html")
+	a.Equal(p.AuthorURL("caixw", 2), "/authors/caixw.html?"+vars.URLQueryPage+"=2")
+	a.Equal(p.AuthorsURL(), "/authors.html")
+	a.Equal(p.AuthorFeedURL("caixw"), "/authors/caixw.xml")
+
+	a.Equal(p.SinglePageURL("about"), "/pages/about.html")
+	a.Equal(p.SinglePageURL("/legal/privacy/"), "/pages/legal/privacy.html")
+
+	a.Equal(p.ArchiveYearURL(2018, 1), "/archives/2018.html")
+	a.Equal(p.ArchiveYearURL(2018, 2), "/archives/2018.html?"+vars.URLQueryPage+"=2")
+	a.Equal(p.ArchiveMonthURL(2018, 1, 1), "/archives/2018/01.html")
+	a.Equal(p.ArchiveMonthURL(2018, 12, 2), "/archives/2018/12.html?"+vars.URLQueryPage+"=2")
+
+	a.Equal(p.SearchURL("", 0), "/search.html")
+	a.Equal(p.SearchURL("", 1), "/search.html")
+	a.Equal(p.SearchURL("", 2), "/search.html?"+vars.URLQueryPage+"=2")
+	a.Equal(p.SearchURL("q", 0), "/search.html?"+vars.URLQuerySearch+"=q")
+	a.Equal(p.SearchURL("q", 1), "/search.html?"+vars.URLQuerySearch+"=q")
+	a.Equal(p.SearchURL("q", 2), "/search.html?"+vars.URLQueryPage+"=2&"+vars.URLQuerySearch+"=q")
+	a.Equal(p.SearchURL("a&b c", 0), "/search.html?"+vars.URLQuerySearch+"=a%26b+c")
+	a.Equal(p.SearchURL("title:go", 0), "/search.html?"+vars.URLQuerySearch+"=title%3Ago")
+	a.Equal(p.SearchTemplateURL("{searchTerms}"), "/search.html?"+vars.URLQuerySearch+"={searchTerms}")
+
+	a.Equal(p.PageRoute("/index.html"), "")
+	a.Equal(p.PageQuery(), vars.URLQueryPage)
+
+	// 自定义的格式
+	p = &Permalinks{Post: "/:year/:month/:day/:slug/", Tag: "/tags/:slug/", Index: "/", Search: "/search/", Page: "page/:page/"}
+	a.Nil(p.sanitize(""))
+
+	a.Equal(p.PostURL("/folder/1", created), "/2018/07/03/folder/1/")
+	a.Equal(p.PostsURL(2), "/page/2/")
+	a.Equal(p.TagURL("go", 1), "/tags/go/")
+	a.Equal(p.TagURL("go", 3), "/tags/go/page/3/")
+	a.Equal(p.AuthorURL("caixw", 3), "/authors/caixw/page/3/") // 默认值以 / 结尾
+	a.Equal(p.ArchiveYearURL(2018, 2), "/archives/2018/page/2/")
+	a.Equal(p.ArchiveMonthURL(2018, 7, 1), "/archives/2018/07/")
+	a.Equal(p.SearchURL("q", 2), "/search/page/2/?q=q")
+	a.Equal(p.SearchURL("a&b", 2), "/search/page/2/?q=a%26b")
+	a.Equal(p.PageRoute("/tags/go/"), "/tags/go/page/{page}/")
+	a.Equal(p.PageQuery(), "")
+
+	// 部署在子目录下
+	p = &Permalinks{}
+	a.Nil(p.sanitize("/blog"))
+
+	a.Equal(p.Root(), "/blog")
+	a.Equal(p.PostURL("1", created), "/blog/posts/1.html")
+	a.Equal(p.PostsURL(1), "/blog/")
+	a.Equal(p.PostsURL(2), "/blog/index.html?"+vars.URLQueryPage+"=2")
+	a.Equal(p.TagURL("go", 1), "/blog/tags/go.html")
+	a.Equal(p.SearchURL("q", 0), "/blog/search.html?"+vars.URLQuerySearch+"=q")
+	a.Equal(p.SearchTemplateURL("{searchTerms}"), "/blog/search.html?"+vars.URLQuerySearch+"={searchTerms}")
+	a.Equal(p.ThemeURL("style.css"), "/blog"+vars.ThemeURL("style.css"))
+	a.Equal(p.AssetURL("1/a.png"), "/blog"+vars.AssetURL("1/a.png"))
+	a.Equal(p.ImageURL("a.png"), "/blog"+vars.ImageURL("a.png"))
+	a.Equal(p.URL("/rss.xml"), "/blog/rss.xml")
+	a.Equal(p.URL("rss.xml"), "rss.xml")
+	a.Equal(p.URL("//example.com/rss.xml"), "//example.com/rss.xml")
+	a.Equal(p.URL("https://example.com/rss.xml"), "https://example.com/rss.xml")
+	a.Equal(p.URL(""), "")
+
+	// 带域名的网站地址
+	p = &Permalinks{}
+	a.Nil(p.sanitize("https://example.com/blog/"))
+
+	a.Equal(p.Root(), "/blog")
+	a.Equal(p.SiteURL(), "https://example.com/blog")
+	a.Equal(p.AbsURL(""), "https://example.com/blog")
+	a.Equal(p.AbsURL(p.PostURL("1", created)), "https://example.com/blog/posts/1.html")
+	a.Equal(p.AbsURL("rss.xml"), "https://example.com/blog/rss.xml")
+	a.Equal(p.AbsURL("http://example.org/1.png"), "http://example.org/1.png")
+}
@@ -5,10 +5,10 @@
 package loader
 
 import (
-	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,17 +39,25 @@ type Post struct {
 	Title    string    `yaml:"title"`    // 标题
 	Created  time.Time `yaml:"created"`  // 创建时间
 	Modified time.Time `yaml:"modified"` // 修改时间
-	Summary  string    `yaml:"summary"`  // 摘要，同时也作为 meta.description 的内容
+	Summary  string    `yaml:"summary"`  // 摘要，同时也作为 meta.description 的内容，为空则自动生成
 
 	// 这两个变量，并不直接对应变量
 	Slug    string `yaml:"-"` // 唯一名称
 	Content string `yaml:"-"` // 内容
 
+	// 实际加载的 meta.yaml 路径，其它语言版本可能为 meta.{lang}.yaml，用于输出错误信息。
+	Meta string `yaml:"-"`
+
 	// 关联的标签列表，以半角逗号分隔的字符串，
 	// 标签名为各个标签的 slug 值，可以保证其唯一。
 	// 最终会被解析到 Tags 中，TagString 会被废弃。
 	Tags string `yaml:"tags"`
 
+	// 在专题中的序号，从 1 开始，为 0 表示未指定。
+	// 若文章属于某一专题，则专题中的文章按此值排序，
+	// 未指定该值的文章排在最后，并按创建时间排序。
+	Part int `yaml:"part,omitempty"`
+
 	// Outdated 用户记录文章的一个过时情况，可以由以下几种值构成：
 	// - created 表示该篇文章以创建时间来计算其是否已经过时，该值也是默认值；
 	// - modified 表示该文章以其修改时间来计算其是否已经过时；
@@ -67,18 +75,43 @@ type Post struct {
 	// 封面地址，可以为空。
 	Image string `yaml:"image,omitempty"`
 
+	// 附件，比如播客的音频文件，可以为空。
+	Enclosure *Enclosure `yaml:"enclosure,omitempty"`
+
+	// 文章的其它地址，访问这些地址时，会以 301 跳转到当前文章。
+	// 以 / 开头的会被当作完整的路径，否则当作文章以前的 slug。
+	Aliases []string `yaml:"aliases,omitempty"`
+
+	// 是否将内容中 $...$ 和 $$...$$ 之间的 LaTeX 公式转换成 MathML，默认为 false。
+	Math bool `yaml:"math,omitempty"`
+
 	Keywords string `yaml:"keywords,omitempty"`
 
+	// 文章的作者，为 authors.yaml 中的 id，多个作者以逗号分隔，不能与 author 同时指定。
+	Authors string `yaml:"authors,omitempty"`
+
 	// 以下内容不存在时，则会使用全局的默认选项
 	Author   *Author `yaml:"author,omitempty"`
 	License  *Link   `yaml:"license,omitempty"`
 	Template string  `yaml:"template,omitempty"`
 	Language string  `yaml:"language,omitempty"`
 
+	// 同一篇文章的不同语言版本使用相同的值，
+	// 以 content.{lang}.html 形式存在的其它语言版本会自动关联，不需要指定。
+	TranslationKey string `yaml:"translationKey,omitempty"`
+
 	// 需要被 service worker 缓存的内容。
 	// 如果是带 https 开头的 URL，则直接使用，
 	// 如果是不以 https 开头的 URL，则会被映射到当前主题下。
 	Assets []string `yaml:"assets,omitempty"`
+
+	// 自定义字段，原样传递给模板，比如主题需要的 hideComments 等。
+	Extra Extra `yaml:"extra,omitempty"`
+
+	// 访问保护，可以是 config.yaml 中 protections 的组名，
+	// 也可以是密码的散列值。受保护的文章需要输入密码才能访问，
+	// 且不会自动生成摘要。
+	Protection string `yaml:"protection,omitempty"`
 }
 
 // Outdated 表示每一篇文章的过时情况
@@ -91,7 +124,7 @@ type Outdated struct {
 }
 
 // LoadPosts 加载所有的文件列表
-func LoadPosts(path *path.Path) ([]*Post, error) {
+func LoadPosts(path *path.Path, conf *Config) ([]*Post, error) {
 	dir := path.PostsDir
 	slugs := make([]string, 0, 100)
 
@@ -123,12 +156,15 @@ func LoadPosts(path *path.Path) ([]*Post, error) {
 	// 开始加载文章的具体内容。
 	posts := make([]*Post, 0, len(slugs))
 	for _, slug := range slugs {
-		post, err := loadPost(path, slug)
+		ps, err := loadPost(path, slug, conf)
 		if err != nil {
 			return nil, err
 		}
 
-		if post.State != StateDraft {
+		for _, post := range ps {
+			if post.Summary == "" && post.Protection == "" {
+				post.Summary = buildSummary(post.Content, conf.SummarySize)
+			}
 			posts = append(posts, post)
 		}
 	}
@@ -140,33 +176,111 @@ func LoadPosts(path *path.Path) ([]*Post, error) {
 	return posts, nil
 }
 
-func loadPost(path *path.Path, slug string) (*Post, error) {
+// 加载 slug 指定的文章，以及以 content.{lang}.html 形式存在的其它语言版本。
+//
+// 文章为草稿时，返回空值。
+func loadPost(path *path.Path, slug string, conf *Config) ([]*Post, error) {
 	post := &Post{}
 	if err := helper.LoadYAMLFile(path.PostMetaPath(slug), post); err != nil {
 		return nil, err
 	}
 	if post.State == StateDraft {
-		return post, nil
+		return nil, nil
+	}
+
+	if err := post.sanitize(slug, path.PostMetaPath(slug), path.PostContentPath(slug)); err != nil {
+		return nil, err
+	}
+	posts := []*Post{post}
+
+	lang := post.Language
+	if lang == "" {
+		lang = conf.Language
+	}
+
+	tags := make([]string, 0, len(conf.Languages)+1)
+	tags = append(tags, conf.Language)
+	for _, l := range conf.Languages {
+		tags = append(tags, l.Tag)
+	}
+
+	for _, tag := range tags {
+		if tag == lang || !utils.FileExists(path.PostLanguageContentPath(slug, tag)) {
+			continue
+		}
+
+		t, err := loadPostTranslation(path, slug, tag)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil { // 草稿
+			continue
+		}
+
+		if post.TranslationKey == "" {
+			post.TranslationKey = slug
+		}
+		t.TranslationKey = post.TranslationKey
+		posts = append(posts, t)
 	}
 
+	return posts, nil
+}
+
+// 加载文章的 lang 语言版本
+//
+// 以 meta.yaml 的内容为基础，若存在 meta.{lang}.yaml，则以其中的内容进行覆盖，
+// 但摘要和 aliases 不会从 meta.yaml 继承。
+func loadPostTranslation(path *path.Path, slug, lang string) (*Post, error) {
+	meta := path.PostMetaPath(slug)
+
+	post := &Post{}
+	if err := helper.LoadYAMLFile(meta, post); err != nil {
+		return nil, err
+	}
+	post.Summary = ""
+	post.Aliases = nil
+
+	if p := path.PostLanguageMetaPath(slug, lang); utils.FileExists(p) {
+		meta = p
+		if err := helper.LoadYAMLFile(meta, post); err != nil {
+			return nil, err
+		}
+	}
+
+	if post.State == StateDraft {
+		return nil, nil
+	}
+	post.Language = lang
+
+	if err := post.sanitize(slug, meta, path.PostLanguageContentPath(slug, lang)); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// 加载文章内容并检测各个字段，meta 为错误信息中的文件名。
+func (post *Post) sanitize(slug, meta, content string) error {
 	post.Slug = slug
+	post.Meta = meta
 
 	// 加载内容
-	data, err := ioutil.ReadFile(path.PostContentPath(slug))
+	data, err := ioutil.ReadFile(content)
 	if err != nil {
-		return nil, &helper.FieldError{File: path.PostMetaPath(slug), Message: err.Error(), Field: "path"}
+		return &helper.FieldError{File: meta, Message: err.Error(), Field: "path"}
 	}
 	if len(data) == 0 {
-		return nil, &helper.FieldError{File: path.PostMetaPath(slug), Message: "不能为空", Field: "content"}
+		return &helper.FieldError{File: meta, Message: "不能为空", Field: "content"}
 	}
 	post.Content = string(data)
 
 	if len(post.Title) == 0 {
-		return nil, &helper.FieldError{File: path.PostMetaPath(slug), Message: "不能为空", Field: "title"}
+		return &helper.FieldError{File: meta, Message: "不能为空", Field: "title"}
 	}
 
 	if len(post.Tags) == 0 {
-		return nil, &helper.FieldError{File: path.PostMetaPath(slug), Message: "不能为空", Field: "tags"}
+		return &helper.FieldError{File: meta, Message: "不能为空", Field: "tags"}
 	}
 
 	// state
@@ -175,7 +289,33 @@ func loadPost(path *path.Path, slug string) (*Post, error) {
 	} else if post.State != StateDefault &&
 		post.State != StateLast &&
 		post.State != StateTop {
-		return nil, &helper.FieldError{File: path.PostMetaPath(slug), Message: "无效的值", Field: "order"}
+		return &helper.FieldError{File: meta, Message: "无效的值", Field: "order"}
+	}
+
+	if post.Part < 0 {
+		return &helper.FieldError{File: meta, Message: "不能小于 0", Field: "part"}
+	}
+
+	if post.Enclosure != nil {
+		if err := post.Enclosure.sanitize(); err != nil {
+			err.File = meta
+			err.Field = "enclosure." + err.Field
+			return err
+		}
+	}
+
+	for index, alias := range post.Aliases {
+		if alias != "" && alias[0] != '/' { // 以前的 slug，在 data 包中转换成完整的地址
+			alias = "/" + alias
+		}
+
+		if err := checkRedirectSource(alias); err != "" {
+			return &helper.FieldError{File: meta, Message: err, Field: "aliases[" + strconv.Itoa(index) + "]"}
+		}
+	}
+
+	if post.Authors != "" && post.Author != nil {
+		return &helper.FieldError{File: meta, Message: "不能与 author 同时指定", Field: "authors"}
 	}
 
 	if post.Keywords == "" {
@@ -186,14 +326,14 @@ func loadPost(path *path.Path, slug string) (*Post, error) {
 		post.Template = vars.PagePost
 	}
 
-	return post, nil
+	return nil
 }
 
 // 检测是否存在同名的文章
 func checkPostsDup(posts []*Post) error {
-	count := func(slug string) (cnt int) {
+	count := func(slug, lang string) (cnt int) {
 		for _, post := range posts {
-			if post.Slug == slug {
+			if post.Slug == slug && post.Language == lang {
 				cnt++
 			}
 		}
@@ -201,8 +341,8 @@ func checkPostsDup(posts []*Post) error {
 	}
 
 	for _, post := range posts {
-		if count(post.Slug) > 1 {
-			return errors.New("存在同名的文章：" + post.Slug)
+		if count(post.Slug, post.Language) > 1 {
+			return helper.Errorf("存在同名的文章：%s", post.Slug)
 		}
 	}
 
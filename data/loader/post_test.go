@@ -19,6 +19,7 @@ func TestCheckPostsDup(t *testing.T) {
 		{Slug: "1"},
 		{Slug: "2"},
 		{Slug: "3"},
+		{Slug: "1", Language: "en"}, // 不同语言的版本
 	}
 	a.NotError(checkPostsDup(posts))
 
@@ -28,27 +29,70 @@ func TestCheckPostsDup(t *testing.T) {
 
 func TestLoadPost(t *testing.T) {
 	a := assert.New(t)
+	conf := &Config{Language: "zh-cmn-Hans", Languages: []*Language{{Tag: "en"}, {Tag: "ja"}}}
 
-	post, err := loadPost(testdataPath, "/post1")
-	a.NotError(err).NotNil(post)
+	posts, err := loadPost(testdataPath, "/post1", conf)
+	a.NotError(err).Equal(len(posts), 2)
+	post := posts[0]
 	a.Equal(post.Tags, "default1,default2")
 	a.Equal(post.Template, vars.PagePost) // 未指定，则为默认值
-	a.Equal(post.Content, "<article>a1</article>\n")
+	a.Equal(post.TranslationKey, "/post1")
+	a.Equal(post.Meta, testdataPath.PostMetaPath("/post1"))
 
-	post, err = loadPost(testdataPath, "/folder/post2")
-	a.NotError(err).NotNil(post)
+	// 短代码在 data 包中展开
+	a.Equal(post.Content, "<article>a1</article>\n{{< gist user=\"caixw\" id=\"1\" >}}\n")
+
+	// content.en.html 和 meta.en.yaml
+	en := posts[1]
+	a.Equal(en.Slug, "/post1").
+		Equal(en.Language, "en").
+		Equal(en.TranslationKey, "/post1").
+		Equal(en.Title, "post1 in english").
+		Equal(en.Tags, post.Tags). // 继承 meta.yaml 中的内容
+		Equal(en.Summary, "").     // 摘要不继承
+		Equal(en.Content, "<article>a1 in english</article>\n").
+		Equal(en.Meta, testdataPath.PostLanguageMetaPath("/post1", "en"))
+
+	posts, err = loadPost(testdataPath, "/folder/post2", conf)
+	a.NotError(err).Equal(len(posts), 1)
+	post = posts[0]
 	a.Equal(post.Slug, "/folder/post2")
 	a.Equal(post.Template, "t1post") // 模板
+	a.Empty(post.TranslationKey)
 
-	post, err = loadPost(testdataPath, "/draft")
-	a.NotError(err).NotNil(post)
-	a.Equal(post.State, StateDraft)
+	// 以前的 slug 在 data 包中转换成完整的地址
+	a.Equal(post.Aliases, []string{"old-post2", "/2016/post2.html"})
+
+	// 草稿
+	posts, err = loadPost(testdataPath, "/draft", conf)
+	a.NotError(err).Empty(posts)
 }
 
 func TestLoadPosts(t *testing.T) {
 	a := assert.New(t)
 
-	posts, err := LoadPosts(testdataPath)
+	conf := &Config{SummarySize: 200}
+	posts, err := LoadPosts(testdataPath, conf)
 	a.NotError(err).NotNil(posts)
-	a.Equal(len(posts), 2) // 只有两条记录，Draft=true 的没有被加载
+	a.Equal(len(posts), 3) // 只有三条记录，Draft=true 的没有被加载
+	for _, post := range posts {
+		if post.Slug == "protected" {
+			a.Equal(post.Protection, "friends").Empty(post.Summary) // 受保护的文章不自动生成摘要
+		}
+	}
+
+	// 包含英文版本
+	conf = &Config{SummarySize: 200, Language: "zh-cmn-Hans", Languages: []*Language{{Tag: "en"}}}
+	posts, err = LoadPosts(testdataPath, conf)
+	a.NotError(err).NotNil(posts)
+	a.Equal(len(posts), 4)
+	var en *Post
+	for _, post := range posts {
+		if post.Language == "en" {
+			en = post
+		}
+	}
+	a.NotNil(en).
+		Equal(en.Slug, "post1").
+		NotEmpty(en.Summary) // 根据内容生成摘要
 }
@@ -0,0 +1,109 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// 密码散列值的格式为 pbkdf2-sha256$迭代次数$盐值$散列值，
+// 盐值和散列值以十六进制表示。
+const (
+	passwordHashPrefix = "pbkdf2-sha256"
+	passwordIterations = 100000
+	passwordSaltSize   = 16
+)
+
+var errInvalidPasswordHash = errors.New("无效的密码散列值")
+
+// HashPassword 生成密码的散列值
+//
+// 散列值采用加盐的 PBKDF2-HMAC-SHA256 算法，可直接用于
+// config.yaml 的 protections 和文章的 protection。
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2([]byte(password), salt, passwordIterations)
+
+	return strings.Join([]string{
+		passwordHashPrefix,
+		strconv.Itoa(passwordIterations),
+		hex.EncodeToString(salt),
+		hex.EncodeToString(key),
+	}, "$"), nil
+}
+
+// CheckPassword 检测密码 password 是否与散列值 hash 匹配
+func CheckPassword(hash, password string) bool {
+	iter, salt, key, err := parsePasswordHash(hash)
+	if err != nil {
+		return false
+	}
+
+	sum := pbkdf2([]byte(password), salt, iter)
+	return subtle.ConstantTimeCompare(sum, key) == 1
+}
+
+// IsPasswordHash 判断 hash 是否为 HashPassword 生成的散列值
+func IsPasswordHash(hash string) bool {
+	_, _, _, err := parsePasswordHash(hash)
+	return err == nil
+}
+
+func parsePasswordHash(hash string) (iter int, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordHashPrefix {
+		return 0, nil, nil, errInvalidPasswordHash
+	}
+
+	iter, err = strconv.Atoi(parts[1])
+	if err != nil || iter <= 0 {
+		return 0, nil, nil, errInvalidPasswordHash
+	}
+
+	salt, err = hex.DecodeString(parts[2])
+	if err != nil || len(salt) == 0 {
+		return 0, nil, nil, errInvalidPasswordHash
+	}
+
+	key, err = hex.DecodeString(parts[3])
+	if err != nil || len(key) != sha256.Size {
+		return 0, nil, nil, errInvalidPasswordHash
+	}
+
+	return iter, salt, key, nil
+}
+
+// 以 HMAC-SHA256 为伪随机函数的 PBKDF2，输出的长度与 SHA256 相同，
+// 所以只需要计算第一个分块。
+func pbkdf2(password, salt []byte, iter int) []byte {
+	prf := hmac.New(sha256.New, password)
+	prf.Write(salt)
+	prf.Write([]byte{0, 0, 0, 1})
+	u := prf.Sum(nil)
+
+	key := make([]byte, len(u))
+	copy(key, u)
+	for i := 1; i < iter; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+
+	return key
+}
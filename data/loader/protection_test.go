@@ -0,0 +1,56 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestHashPassword(t *testing.T) {
+	a := assert.New(t)
+
+	h1, err := HashPassword("123")
+	a.NotError(err).True(IsPasswordHash(h1))
+	a.True(CheckPassword(h1, "123")).
+		False(CheckPassword(h1, "1234")).
+		False(CheckPassword(h1, ""))
+
+	// 每次的盐值都不同
+	h2, err := HashPassword("123")
+	a.NotError(err).NotEqual(h1, h2)
+	a.True(CheckPassword(h2, "123"))
+
+	a.False(CheckPassword("", "123"))
+}
+
+func TestIsPasswordHash(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(IsPasswordHash("pbkdf2-sha256$1$73616c74$120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"))
+	a.False(IsPasswordHash(""))
+	a.False(IsPasswordHash("friends"))
+
+	// 不加盐的 SHA256 不再有效
+	a.False(IsPasswordHash("a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"))
+
+	a.False(IsPasswordHash("bcrypt$1$73616c74$120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"))
+	a.False(IsPasswordHash("pbkdf2-sha256$0$73616c74$120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"))
+	a.False(IsPasswordHash("pbkdf2-sha256$1$$120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"))
+	a.False(IsPasswordHash("pbkdf2-sha256$1$73616c74$120fb6cf"))
+}
+
+func TestPBKDF2(t *testing.T) {
+	a := assert.New(t)
+
+	// RFC 7914 中的测试数据
+	a.Equal(hex.EncodeToString(pbkdf2([]byte("passwd"), []byte("salt"), 1)),
+		"55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc")
+
+	a.Equal(hex.EncodeToString(pbkdf2([]byte("password"), []byte("salt"), 4096)),
+		"c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a")
+}
@@ -4,11 +4,7 @@
 
 package loader
 
-import (
-	"github.com/issue9/web"
-
-	"github.com/caixw/gitype/helper"
-)
+import "github.com/caixw/gitype/helper"
 
 // PWA 表示 PWA 中的相关配置
 type PWA struct {
@@ -71,7 +67,12 @@ func (m *Manifest) sanitize(conf *Config) *helper.FieldError {
 	}
 
 	if m.StartURL == "" {
-		m.StartURL = web.URL("")
+		m.StartURL = conf.Permalinks.AbsURL(conf.Permalinks.PostsURL(1))
+	}
+
+	// 网站部署在子目录下时，默认的作用域应该是该子目录，而不是 start_url 所在的目录。
+	if m.Scope == "" && conf.Permalinks.Root() != "" {
+		m.Scope = conf.Permalinks.PostsURL(1)
 	}
 
 	if m.Display == "" {
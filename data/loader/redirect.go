@@ -0,0 +1,104 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/issue9/utils"
+
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+)
+
+// Redirect 表示一条重定向规则
+type Redirect struct {
+	Source string `yaml:"source"`           // 原地址，以 / 开头，不能包含域名
+	Target string `yaml:"target,omitempty"` // 目标地址，可以是带域名的地址，status 为 410 时必须为空
+	Status int    `yaml:"status,omitempty"` // 状态码，可以是 301（默认值）、302、307、308 和 410
+}
+
+// LoadRedirects 加载重定向的配置内容
+//
+// 该文件是可选的，不存在时返回空值。
+func LoadRedirects(path *path.Path) ([]*Redirect, error) {
+	if !utils.FileExists(path.MetaRedirectsFile) {
+		return nil, nil
+	}
+
+	redirects := make([]*Redirect, 0, 20)
+	if err := helper.LoadYAMLFile(path.MetaRedirectsFile, &redirects); err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]bool, len(redirects))
+	for index, r := range redirects {
+		if err := r.sanitize(); err != nil {
+			err.File = path.MetaRedirectsFile
+			err.Field = "[" + strconv.Itoa(index) + "]." + err.Field
+			return nil, err
+		}
+
+		if sources[r.Source] {
+			return nil, &helper.FieldError{
+				File:    path.MetaRedirectsFile,
+				Message: "重复的值",
+				Field:   "[" + strconv.Itoa(index) + "].source",
+			}
+		}
+		sources[r.Source] = true
+	}
+
+	return redirects, nil
+}
+
+func (r *Redirect) sanitize() *helper.FieldError {
+	if err := checkRedirectSource(r.Source); err != "" {
+		return &helper.FieldError{Message: err, Field: "source"}
+	}
+
+	switch r.Status {
+	case 0:
+		r.Status = http.StatusMovedPermanently
+	case http.StatusMovedPermanently, http.StatusFound,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect, http.StatusGone:
+	default:
+		return &helper.FieldError{Message: "无效的状态码", Field: "status"}
+	}
+
+	if r.Status == http.StatusGone {
+		if r.Target != "" {
+			return &helper.FieldError{Message: "状态码为 410 时不能指定该值", Field: "target"}
+		}
+		return nil
+	}
+
+	if r.Target == "" {
+		return &helper.FieldError{Message: "不能为空", Field: "target"}
+	}
+	if r.Target == r.Source {
+		return &helper.FieldError{Message: "不能与 source 相同", Field: "target"}
+	}
+
+	return nil
+}
+
+// 检测重定向的原地址是否合法，合法则返回空字符串，否则返回错误信息。
+//
+// 原地址会被直接注册为路由项，所以不能包含路由的参数语法。
+func checkRedirectSource(source string) string {
+	switch {
+	case source == "":
+		return "不能为空"
+	case source[0] != '/':
+		return "必须以 / 开头"
+	case strings.ContainsAny(source, "{}?#"):
+		return "不能包含 {、}、? 和 # 等字符"
+	}
+
+	return ""
+}
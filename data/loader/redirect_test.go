@@ -0,0 +1,63 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestLoadRedirects(t *testing.T) {
+	a := assert.New(t)
+
+	redirects, err := LoadRedirects(testdataPath)
+	a.NotError(err).Equal(len(redirects), 3)
+
+	a.Equal(redirects[0].Source, "/about.html")
+	a.Equal(redirects[0].Status, http.StatusMovedPermanently) // 默认值
+	a.Equal(redirects[1].Status, http.StatusFound)
+	a.Equal(redirects[2].Status, http.StatusGone)
+	a.Empty(redirects[2].Target)
+}
+
+func TestRedirect_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	r := &Redirect{Source: "/old.html", Target: "/new.html"}
+	a.Nil(r.sanitize())
+	a.Equal(r.Status, http.StatusMovedPermanently)
+
+	// 410 不能指定 target
+	r = &Redirect{Source: "/old.html", Target: "/new.html", Status: http.StatusGone}
+	a.Equal(r.sanitize().Field, "target")
+
+	// 缺少 target
+	r = &Redirect{Source: "/old.html"}
+	a.Equal(r.sanitize().Field, "target")
+
+	// 与 source 相同
+	r = &Redirect{Source: "/old.html", Target: "/old.html"}
+	a.Equal(r.sanitize().Field, "target")
+
+	// 无效的状态码
+	r = &Redirect{Source: "/old.html", Target: "/new.html", Status: http.StatusOK}
+	a.Equal(r.sanitize().Field, "status")
+
+	// 无效的 source
+	r = &Redirect{Source: "old.html", Target: "/new.html"}
+	a.Equal(r.sanitize().Field, "source")
+}
+
+func TestCheckRedirectSource(t *testing.T) {
+	a := assert.New(t)
+
+	a.Empty(checkRedirectSource("/old.html"))
+	a.NotEmpty(checkRedirectSource(""))
+	a.NotEmpty(checkRedirectSource("old.html"))
+	a.NotEmpty(checkRedirectSource("/{slug}.html"))
+	a.NotEmpty(checkRedirectSource("/old.html?id=1"))
+}
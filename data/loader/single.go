@@ -0,0 +1,126 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/issue9/utils"
+
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+	"github.com/caixw/gitype/vars"
+)
+
+// SinglePage 表示 pages 目录下的独立页面，比如关于、隐私政策等。
+//
+// 独立页面不会出现在文章列表、RSS、Atom 和归档中，但会出现在 sitemap 中。
+type SinglePage struct {
+	Title    string    `yaml:"title"`
+	Modified time.Time `yaml:"modified"`
+	Summary  string    `yaml:"summary,omitempty"` // meta.description 的内容
+	Keywords string    `yaml:"keywords,omitempty"`
+
+	Slug    string `yaml:"-"` // 唯一名称，即相对于 pages 目录的路径
+	Content string `yaml:"-"` // 内容
+
+	// 页面的地址，必须以 / 开头，为空则根据 permalinks.single 生成。
+	Permalink string `yaml:"permalink,omitempty"`
+
+	// 是否为草稿，草稿不会被加载
+	Draft bool `yaml:"draft,omitempty"`
+
+	Template string `yaml:"template,omitempty"` // 使用的模板，默认为 page
+	Language string `yaml:"language,omitempty"` // 所属的语言，默认为网站的默认语言
+}
+
+// LoadSinglePages 加载 pages 目录下的所有独立页面
+//
+// 该目录是可选的，不存在时返回空值。
+func LoadSinglePages(path *path.Path) ([]*SinglePage, error) {
+	if !utils.FileExists(path.PagesDir) {
+		return nil, nil
+	}
+
+	slugs := make([]string, 0, 10)
+	pagesDir := filepath.Clean(path.PagesDir)
+	walk := func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		slug := strings.TrimPrefix(p, pagesDir) // 获取相对于 data/pages 的名称
+		slug = strings.Trim(filepath.ToSlash(slug), "/")
+
+		if utils.FileExists(path.PageContentPath(slug)) &&
+			utils.FileExists(path.PageMetaPath(slug)) {
+			slugs = append(slugs, slug)
+		}
+		return nil
+	}
+	if err := filepath.Walk(pagesDir, walk); err != nil {
+		return nil, err
+	}
+
+	pages := make([]*SinglePage, 0, len(slugs))
+	for _, slug := range slugs {
+		page := &SinglePage{}
+		if err := helper.LoadYAMLFile(path.PageMetaPath(slug), page); err != nil {
+			return nil, err
+		}
+		if page.Draft {
+			continue
+		}
+
+		if err := page.sanitize(path, slug); err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+func (page *SinglePage) sanitize(path *path.Path, slug string) *helper.FieldError {
+	page.Slug = slug
+	meta := path.PageMetaPath(slug)
+
+	data, err := ioutil.ReadFile(path.PageContentPath(slug))
+	if err != nil {
+		return &helper.FieldError{File: meta, Message: err.Error(), Field: "path"}
+	}
+	if len(data) == 0 {
+		return &helper.FieldError{File: meta, Message: "不能为空", Field: "content"}
+	}
+	page.Content = string(data)
+
+	if len(page.Title) == 0 {
+		return &helper.FieldError{File: meta, Message: "不能为空", Field: "title"}
+	}
+
+	if page.Permalink != "" {
+		if page.Permalink[0] != '/' {
+			return &helper.FieldError{File: meta, Message: "必须以 / 开头", Field: "permalink"}
+		}
+
+		if strings.ContainsAny(page.Permalink, "{}?#") {
+			return &helper.FieldError{File: meta, Message: "不能包含 {、}、? 和 # 等字符", Field: "permalink"}
+		}
+	}
+
+	if page.Template == "" {
+		page.Template = vars.PageSingle
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/vars"
+)
+
+func TestLoadSinglePages(t *testing.T) {
+	a := assert.New(t)
+
+	pages, err := LoadSinglePages(testdataPath)
+	a.NotError(err).Equal(len(pages), 1)
+
+	page := pages[0]
+	a.Equal(page.Slug, "privacy").
+		Equal(page.Title, "隐私政策").
+		Equal(page.Template, vars.PageSingle).
+		NotEmpty(page.Content)
+}
+
+func TestSinglePage_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	page := &SinglePage{}
+	a.Equal(page.sanitize(testdataPath, "privacy").Field, "title")
+
+	page = &SinglePage{Title: "title", Permalink: "about.html"}
+	a.Equal(page.sanitize(testdataPath, "privacy").Field, "permalink")
+
+	page = &SinglePage{Title: "title", Permalink: "/about.html"}
+	a.NotError(page.sanitize(testdataPath, "privacy"))
+
+	// 不存在的内容文件
+	page = &SinglePage{Title: "title"}
+	a.Equal(page.sanitize(testdataPath, "not-exists").Field, "path")
+}
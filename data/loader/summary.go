@@ -0,0 +1,117 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 文章内容中的分隔符，之前的内容会被当作摘要。
+const moreSeparator = "<!--more-->"
+
+// 默认的摘要长度，以字符计，可以通过 config.summarySize 修改。
+const defaultSummarySize = 200
+
+// 句子的结束符号，截取摘要时，尽量在这些符号之后截断。
+const sentenceTerminators = "。！？；…!?;"
+
+// 短代码的标记，在 data 包中才会被展开，生成摘要时直接去掉。
+var shortcodeExpr = regexp.MustCompile(`(?s){{<.*?>}}`)
+
+// 从文章内容中生成摘要
+//
+// 若内容中包含 <!--more-->，则以其之前的内容作为摘要；
+// 否则取内容的前 size 个字符，并尽量在句子结束的地方截断。
+func buildSummary(content string, size int) string {
+	content = shortcodeExpr.ReplaceAllString(content, "")
+
+	if index := strings.Index(content, moreSeparator); index >= 0 {
+		return plainText(content[:index])
+	}
+
+	text := []rune(plainText(content))
+	if len(text) <= size {
+		return string(text)
+	}
+
+	for i := size - 1; i > 0; i-- {
+		if isSentenceEnd(text, i) {
+			return string(text[:i+1])
+		}
+	}
+
+	return strings.TrimSpace(string(text[:size])) + "…"
+}
+
+// 判断 text[i] 是否为一个句子的结束
+//
+// 英文的句号需要后跟空白字符，防止将诸如 1.11 之类的内容当作句子结束。
+func isSentenceEnd(text []rune, i int) bool {
+	r := text[i]
+	if strings.ContainsRune(sentenceTerminators, r) {
+		return true
+	}
+
+	return r == '.' && i+1 < len(text) && unicode.IsSpace(text[i+1])
+}
+
+// 行内元素，去掉标签时，不需要在其前后添加空格。
+var inlineElements = map[atom.Atom]bool{
+	atom.A:      true,
+	atom.Abbr:   true,
+	atom.B:      true,
+	atom.Cite:   true,
+	atom.Code:   true,
+	atom.Del:    true,
+	atom.Em:     true,
+	atom.I:      true,
+	atom.Ins:    true,
+	atom.Kbd:    true,
+	atom.Mark:   true,
+	atom.Q:      true,
+	atom.S:      true,
+	atom.Small:  true,
+	atom.Span:   true,
+	atom.Strong: true,
+	atom.Sub:    true,
+	atom.Sup:    true,
+	atom.Time:   true,
+	atom.U:      true,
+	atom.Var:    true,
+}
+
+// 去掉 HTML 标签，并将连续的空白字符合并为一个空格。
+func plainText(content string) string {
+	buf := new(bytes.Buffer)
+	z := html.NewTokenizer(strings.NewReader(content))
+	skip := false // script 和 style 中的内容不需要
+
+LOOP:
+	for {
+		switch typ := z.Next(); typ {
+		case html.ErrorToken:
+			break LOOP
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			skip = (a == atom.Script || a == atom.Style) && typ == html.StartTagToken
+			if !inlineElements[a] {
+				buf.WriteByte(' ')
+			}
+		case html.TextToken:
+			if !skip {
+				buf.Write(z.Text())
+			}
+		}
+	}
+
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestBuildSummary(t *testing.T) {
+	a := assert.New(t)
+
+	// <!--more-->
+	a.Equal(buildSummary("<p>abc <b>def</b></p><!--more--><p>ghi</p>", 2), "abc def")
+
+	// 长度足够
+	a.Equal(buildSummary("<p>abc</p><p>def</p>", 200), "abc def")
+
+	// 在句子结束处截断
+	a.Equal(buildSummary("<p>go 1.11 released. it is great</p>", 25), "go 1.11 released.")
+	a.Equal(buildSummary("<p>第一句。第二句。第三句</p>", 6), "第一句。")
+
+	// 短代码
+	a.Equal(buildSummary(`<p>abc</p>{{< figure src="1.png" >}}{{< quote >}}def{{< /quote >}}`, 200), "abc def")
+
+	// 找不到句子结束的地方
+	a.Equal(buildSummary("<p>abcdefghijk</p>", 5), "abcde…")
+}
+
+func TestPlainText(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(plainText("<p>a<b>b</b>c</p><p>d &amp; e</p>"), "abc d & e")
+	a.Equal(plainText("<p>a</p><script>var x</script><style>*{}</style>b"), "a b")
+	a.Equal(plainText("中<br />文"), "中 文")
+}
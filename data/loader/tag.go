@@ -5,7 +5,6 @@
 package loader
 
 import (
-	"errors"
 	"strconv"
 
 	"github.com/caixw/gitype/helper"
@@ -22,6 +21,15 @@ type Tag struct {
 	Color   string `yaml:"color,omitempty"` // 标签颜色。若未指定，则继承父容器
 	Content string `yaml:"content"`         // 对该标签的详细描述
 	Series  bool   `yaml:"series"`          // 是否为一个专题标签
+
+	// 父标签的 slug，可以为空。父标签的页面会同时列出所有子孙标签的文章。
+	Parent string `yaml:"parent,omitempty"`
+
+	// 同义词，文章中引用这些值时，等同于引用当前标签。
+	Synonyms []string `yaml:"synonyms,omitempty"`
+
+	// 自定义字段，原样传递给模板
+	Extra Extra `yaml:"extra,omitempty"`
 }
 
 // LoadTags 加载标签内容
@@ -44,6 +52,11 @@ func LoadTags(path *path.Path) ([]*Tag, error) {
 		return nil, err
 	}
 
+	if err := checkTagsParent(tags); err != nil {
+		err.File = path.MetaTagsFile
+		return nil, err
+	}
+
 	return tags, nil
 }
 
@@ -60,7 +73,68 @@ func checkTagsDup(tags []*Tag) error {
 
 	for _, tag := range tags {
 		if count(tag.Slug) > 1 {
-			return errors.New("存在同名的标签：" + tag.Slug)
+			return helper.Errorf("存在同名的标签：%s", tag.Slug)
+		}
+
+		// 同义词不能与其它标签或是同义词相同
+		for _, synonym := range tag.Synonyms {
+			if count(synonym) > 0 {
+				return helper.Errorf("存在同名的标签：%s", synonym)
+			}
+
+			cnt := 0
+			for _, t := range tags {
+				for _, s := range t.Synonyms {
+					if s == synonym {
+						cnt++
+					}
+				}
+			}
+			if cnt > 1 {
+				return helper.Errorf("存在同名的标签：%s", synonym)
+			}
+		}
+	}
+
+	return nil
+}
+
+// 检测标签的父标签是否存在，以及是否存在循环引用。
+func checkTagsParent(tags []*Tag) *helper.FieldError {
+	slugs := make(map[string]*Tag, len(tags))
+	for _, tag := range tags {
+		slugs[tag.Slug] = tag
+	}
+
+	for index, tag := range tags {
+		if tag.Parent == "" {
+			continue
+		}
+
+		field := "[" + strconv.Itoa(index) + "].parent"
+
+		// 父标签会合并子孙标签的文章，而专题中的文章需要按 part 排序，
+		// 合并之后序号会相互冲突，所以专题不能有层级关系。
+		if tag.Series {
+			return &helper.FieldError{Message: "专题不能指定父标签", Field: field}
+		}
+
+		parent, found := slugs[tag.Parent]
+		if !found {
+			return &helper.FieldError{Message: "不存在的标签", Field: field}
+		}
+
+		// 专题与标签不能相互嵌套
+		if parent.Series != tag.Series {
+			return &helper.FieldError{Message: "专题与标签不能相互嵌套", Field: field}
+		}
+
+		// 沿父标签向上查找，最多经过 len(tags) 个标签即应到达顶级标签
+		for i := 0; parent != nil; i++ {
+			if parent == tag || i >= len(tags) {
+				return &helper.FieldError{Message: "存在循环引用", Field: field}
+			}
+			parent = slugs[parent.Parent]
 		}
 	}
 
@@ -80,5 +154,11 @@ func (tag *Tag) sanitize() *helper.FieldError {
 		return &helper.FieldError{Message: "不能为空", Field: "content"}
 	}
 
+	for index, synonym := range tag.Synonyms {
+		if len(synonym) == 0 {
+			return &helper.FieldError{Message: "不能为空", Field: "synonyms[" + strconv.Itoa(index) + "]"}
+		}
+	}
+
 	return nil
 }
@@ -20,6 +20,8 @@ func TestLoadTags(t *testing.T) {
 	a.Equal(tags[0].Color, "efefef")
 	a.Equal(tags[0].Title, "默认1")
 	a.Equal(tags[1].Slug, "default2")
+	a.Equal(tags[2].Parent, "default1")
+	a.Equal(tags[2].Synonyms, []string{"golang"})
 }
 
 func TestCheckTagsDup(t *testing.T) {
@@ -34,4 +36,60 @@ func TestCheckTagsDup(t *testing.T) {
 
 	tags = append(tags, &Tag{Slug: "1"})
 	a.Error(checkTagsDup(tags))
+
+	// 同义词与标签相同
+	tags = []*Tag{
+		{Slug: "1", Synonyms: []string{"2"}},
+		{Slug: "2"},
+	}
+	a.Error(checkTagsDup(tags))
+
+	// 同义词之间相同
+	tags = []*Tag{
+		{Slug: "1", Synonyms: []string{"3"}},
+		{Slug: "2", Synonyms: []string{"3"}},
+	}
+	a.Error(checkTagsDup(tags))
+}
+
+func TestCheckTagsParent(t *testing.T) {
+	a := assert.New(t)
+
+	tags := []*Tag{
+		{Slug: "1"},
+		{Slug: "2", Parent: "1"},
+		{Slug: "3", Parent: "2"},
+	}
+	a.NotError(checkTagsParent(tags))
+
+	// 不存在的父标签
+	tags[0].Parent = "4"
+	a.Equal(checkTagsParent(tags).Field, "[0].parent")
+
+	// 循环引用
+	tags[0].Parent = "3"
+	a.Equal(checkTagsParent(tags).Message, "存在循环引用")
+
+	// 自身
+	tags[0].Parent = "1"
+	a.Equal(checkTagsParent(tags).Message, "存在循环引用")
+
+	// 专题与标签嵌套
+	tags[0].Parent = ""
+	tags[0].Series = true
+	a.Equal(checkTagsParent(tags).Message, "专题与标签不能相互嵌套")
+
+	// 专题之间嵌套
+	tags = []*Tag{
+		{Slug: "1", Series: true},
+		{Slug: "2", Series: true, Parent: "1"},
+	}
+	err := checkTagsParent(tags)
+	a.NotNil(err).
+		Equal(err.Message, "专题不能指定父标签").
+		Equal(err.Field, "[1].parent")
+
+	// 标签中包含专题
+	tags[1].Series = false
+	a.Equal(checkTagsParent(tags).Message, "专题与标签不能相互嵌套")
 }
@@ -8,10 +8,12 @@ import (
 	"mime"
 	stdpath "path"
 	"strconv"
+	"time"
 
 	"github.com/caixw/gitype/helper"
 	"github.com/caixw/gitype/path"
 	"github.com/issue9/is"
+	l "golang.org/x/text/language"
 )
 
 // Author 描述作者信息
@@ -20,6 +22,11 @@ type Author struct {
 	URL    string `yaml:"url,omitempty"`
 	Email  string `yaml:"email,omitempty"`
 	Avatar string `yaml:"avatar,omitempty"`
+
+	// 以下内容仅对 authors.yaml 中的作者有效
+	ID    string  `yaml:"id,omitempty"`    // 唯一 ID，文章通过该值引用作者，同时也作为地址的一部分
+	Bio   string  `yaml:"bio,omitempty"`   // 简介
+	Links []*Link `yaml:"links,omitempty"` // 社交网络等链接
 }
 
 // Link 描述链接的内容
@@ -31,6 +38,10 @@ type Link struct {
 	URL   string `yaml:"url"`             // 链接地址
 	Text  string `yaml:"text"`            // 链接的文本
 	Type  string `yaml:"type,omitempty"`  // 链接的类型，一般用于 a 和 link 标签的 type 属性
+
+	// 指向 pages 目录下的独立页面，值为页面的 slug。仅对 config.yaml 中的 menus 有效，
+	// 指定之后，url 由页面的地址决定，text 为空时则使用页面的标题。
+	Page string `yaml:"page,omitempty"`
 }
 
 // Icon 表示网站图标，比如 html>head>link.rel="short icon"
@@ -40,6 +51,17 @@ type Icon struct {
 	Sizes string `yaml:"sizes"`
 }
 
+// Enclosure 表示文章的附件，一般用于播客的音频或是视频文件，
+// 最终会被输出到 RSS 和 Atom 中。
+type Enclosure struct {
+	URL      string        `yaml:"url"`                // 附件地址
+	Type     string        `yaml:"type,omitempty"`     // mime type，为空则根据扩展名计算
+	Length   int64         `yaml:"length,omitempty"`   // 文件大小，以字节为单位
+	Duration time.Duration `yaml:"duration,omitempty"` // 播放时长
+	Episode  int           `yaml:"episode,omitempty"`  // 第几集，为 0 表示不输出该值
+	Explicit bool          `yaml:"explicit,omitempty"` // 是否包含成人内容
+}
+
 // Theme 表示主题信息
 type Theme struct {
 	ID          string  `yaml:"-"`    // 唯一 ID，即当前目录名称
@@ -53,6 +75,14 @@ type Theme struct {
 	// 如果是带 https 开头的 URL，则直接使用，
 	// 如果是不以 https 开头的 URL，则会被映射到当前主题下。
 	Assets []string `yaml:"assets,omitempty"`
+
+	// 主题中用到的文字的翻译，键名为语言标签，值为原文与译文的对应关系，
+	// 会覆盖程序内置的同名内容。模板中可以通过 T 函数获取当前语言的译文。
+	Messages map[string]map[string]string `yaml:"messages,omitempty"`
+
+	// 主题用到的自定义字段的类型声明，键名为 post、tag 或是 config，
+	// 值为字段名与类型的对应关系，加载数据时会检测对应的 extra 是否符合声明的类型。
+	Extra map[string]map[string]string `yaml:"extra,omitempty"`
 }
 
 // LoadLinks 加载友情链接的内容
@@ -96,6 +126,17 @@ func LoadTheme(path *path.Path, name string) (*Theme, error) {
 		}
 	}
 
+	for tag := range theme.Messages {
+		if _, err := l.Parse(tag); err != nil {
+			return nil, &helper.FieldError{File: path.ThemeMetaPath(theme.ID), Message: err.Error(), Field: "messages." + tag}
+		}
+	}
+
+	if err := sanitizeThemeExtra(theme.Extra); err != nil {
+		err.File = path.ThemeMetaPath(theme.ID)
+		return nil, err
+	}
+
 	return theme, nil
 }
 
@@ -111,6 +152,30 @@ func (icon *Icon) sanitize() *helper.FieldError {
 	return nil
 }
 
+func (e *Enclosure) sanitize() *helper.FieldError {
+	if len(e.URL) == 0 {
+		return &helper.FieldError{Field: "url", Message: "不能为空"}
+	}
+
+	if e.Type == "" {
+		e.Type = mime.TypeByExtension(stdpath.Ext(e.URL))
+	}
+	if e.Type == "" {
+		return &helper.FieldError{Field: "type", Message: "无法根据扩展名获取，必须指定"}
+	}
+
+	switch {
+	case e.Length < 0:
+		return &helper.FieldError{Field: "length", Message: "不能小于 0"}
+	case e.Duration < 0:
+		return &helper.FieldError{Field: "duration", Message: "不能小于 0"}
+	case e.Episode < 0:
+		return &helper.FieldError{Field: "episode", Message: "不能小于 0"}
+	}
+
+	return nil
+}
+
 func (link *Link) sanitize() *helper.FieldError {
 	if len(link.Text) == 0 {
 		return &helper.FieldError{Field: "text", Message: "不能为空"}
@@ -63,3 +63,25 @@ func TestIcon_sanitize(t *testing.T) {
 	a.NotError(icon.sanitize())
 	a.Equal(icon.Type, "")
 }
+
+func TestEnclosure_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	e := &Enclosure{}
+	a.Error(e.sanitize())
+
+	// 根据扩展名计算 type
+	e.URL = "/episode.mp3"
+	a.NotError(e.sanitize())
+	a.Equal(e.Type, "audio/mpeg")
+
+	// 无法计算 type
+	e = &Enclosure{URL: "/episode.not-exists"}
+	a.Error(e.sanitize())
+
+	e = &Enclosure{URL: "/episode.mp3", Length: -1}
+	a.Error(e.sanitize())
+
+	e = &Enclosure{URL: "/episode.mp3", Episode: -1}
+	a.Error(e.sanitize())
+}
@@ -0,0 +1,618 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/caixw/gitype/helper"
+)
+
+// 公式的分隔符
+const (
+	mathInline  = "$"
+	mathDisplay = "$$"
+)
+
+// 这些元素中的内容不作公式处理
+var mathSkipElements = map[atom.Atom]bool{
+	atom.Code:     true,
+	atom.Pre:      true,
+	atom.Kbd:      true,
+	atom.Samp:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Textarea: true,
+	atom.Math:     true,
+}
+
+// 将内容中的 LaTeX 公式转换成 MathML
+//
+// $$...$$ 为块级公式，$...$ 为行内公式。为了不影响正常的美元符号，
+// 行内公式的开始符号之后和结束符号之前都不能是空白字符，结束符号之后也不能是数字，
+// 比如 $5 和 $10 不会被当作公式；也可以使用 \$ 表示一个普通的美元符号。
+func (c *content) renderMath() error {
+	texts := make([]*html.Node, 0, 10)
+	c.walk(func(n *html.Node) bool {
+		switch {
+		case n.Type == html.ElementNode && mathSkipElements[n.DataAtom]:
+			return false
+		case n.Type == html.TextNode && strings.Contains(n.Data, mathInline):
+			texts = append(texts, n)
+		}
+		return true
+	})
+
+	for _, text := range texts {
+		if err := c.replaceMath(text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 将文本节点 n 中的公式替换成 MathML 节点
+func (c *content) replaceMath(n *html.Node) error {
+	buf := new(bytes.Buffer)
+	plain := new(bytes.Buffer) // 不包含公式时，去掉转义字符之后的内容
+	found := false
+	err := splitMath(n.Data, func(text string, tex string, display bool) error {
+		if tex == "" {
+			buf.WriteString(html.EscapeString(text))
+			plain.WriteString(text)
+			return nil
+		}
+
+		found = true
+		ml, err := texToMathML(tex, display)
+		if err != nil {
+			return helper.Errorf("公式 %s 错误：%s", text, err)
+		}
+		buf.WriteString(ml)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		n.Data = plain.String()
+		return nil
+	}
+
+	parent := n.Parent
+	if parent == nil { // 顶层节点
+		parent = contentContext
+	}
+	nodes, err := html.ParseFragment(buf, parent)
+	if err != nil {
+		return err
+	}
+
+	if n.Parent != nil {
+		for _, node := range nodes {
+			n.Parent.InsertBefore(node, n)
+		}
+		n.Parent.RemoveChild(n)
+		return nil
+	}
+
+	for index, node := range c.nodes {
+		if node == n {
+			c.nodes = append(c.nodes[:index], append(nodes, c.nodes[index+1:]...)...)
+			break
+		}
+	}
+	return nil
+}
+
+// 将文本拆分成普通文本和公式，并依次调用 fn。
+//
+// 对于普通文本，tex 为空；对于公式，text 为包含分隔符在内的原始内容。
+func splitMath(text string, fn func(text, tex string, display bool) error) error {
+	plain := new(bytes.Buffer)
+	flush := func() error {
+		if plain.Len() == 0 {
+			return nil
+		}
+		err := fn(plain.String(), "", false)
+		plain.Reset()
+		return err
+	}
+
+	for i := 0; i < len(text); {
+		switch {
+		case strings.HasPrefix(text[i:], `\`+mathInline): // 转义的美元符号
+			plain.WriteString(mathInline)
+			i += 2
+			continue
+		case strings.HasPrefix(text[i:], mathDisplay):
+			if end := strings.Index(text[i+2:], mathDisplay); end > 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+				end += i + 2
+				if err := fn(text[i:end+2], strings.TrimSpace(text[i+2:end]), true); err != nil {
+					return err
+				}
+				i = end + 2
+				continue
+			}
+		case text[i] == mathInline[0]:
+			if end := inlineMathEnd(text, i); end > 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+				if err := fn(text[i:end+1], text[i+1:end], false); err != nil {
+					return err
+				}
+				i = end + 1
+				continue
+			}
+		}
+
+		plain.WriteByte(text[i])
+		i++
+	}
+
+	return flush()
+}
+
+// 查找从 start 开始的行内公式的结束位置，若不是一个行内公式，则返回 -1。
+func inlineMathEnd(text string, start int) int {
+	if start+1 >= len(text) || isSpaceByte(text[start+1]) {
+		return -1
+	}
+
+	for i := start + 1; i < len(text); i++ {
+		switch {
+		case text[i] == '\\': // 跳过被转义的字符，比如 \$
+			i++
+		case text[i] == mathInline[0]:
+			if isSpaceByte(text[i-1]) {
+				continue
+			}
+			if i+1 < len(text) && text[i+1] >= '0' && text[i+1] <= '9' {
+				continue
+			}
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isSpaceByte(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// texToMathML 将 LaTeX 公式转换成 MathML
+//
+// 仅支持常用的一部分语法：上下标、分数、根式、希腊字母、常用的运算符以及
+// \left 和 \right 等，原始的公式会作为 annotation 保存在结果中。
+func texToMathML(tex string, display bool) (string, error) {
+	p := &texParser{src: []rune(tex)}
+	body, err := p.parseExpr(false)
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.src) {
+		return "", helper.Errorf("多余的字符 %c", p.src[p.pos])
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML"`)
+	if display {
+		buf.WriteString(` display="block"`)
+	}
+	buf.WriteString(`><semantics><mrow>`)
+	buf.WriteString(body)
+	buf.WriteString(`</mrow><annotation encoding="application/x-tex">`)
+	buf.WriteString(html.EscapeString(tex))
+	buf.WriteString(`</annotation></semantics></math>`)
+
+	return buf.String(), nil
+}
+
+// 命令对应的符号
+type texSymbol struct {
+	tag  string // mi 或 mo
+	text string
+}
+
+var texSymbols = map[string]texSymbol{
+	// 希腊字母
+	"alpha": {"mi", "α"}, "beta": {"mi", "β"}, "gamma": {"mi", "γ"}, "delta": {"mi", "δ"},
+	"epsilon": {"mi", "ϵ"}, "varepsilon": {"mi", "ε"}, "zeta": {"mi", "ζ"}, "eta": {"mi", "η"},
+	"theta": {"mi", "θ"}, "vartheta": {"mi", "ϑ"}, "iota": {"mi", "ι"}, "kappa": {"mi", "κ"},
+	"lambda": {"mi", "λ"}, "mu": {"mi", "μ"}, "nu": {"mi", "ν"}, "xi": {"mi", "ξ"},
+	"pi": {"mi", "π"}, "rho": {"mi", "ρ"}, "sigma": {"mi", "σ"}, "tau": {"mi", "τ"},
+	"upsilon": {"mi", "υ"}, "phi": {"mi", "ϕ"}, "varphi": {"mi", "φ"}, "chi": {"mi", "χ"},
+	"psi": {"mi", "ψ"}, "omega": {"mi", "ω"},
+	"Gamma": {"mi", "Γ"}, "Delta": {"mi", "Δ"}, "Theta": {"mi", "Θ"}, "Lambda": {"mi", "Λ"},
+	"Xi": {"mi", "Ξ"}, "Pi": {"mi", "Π"}, "Sigma": {"mi", "Σ"}, "Upsilon": {"mi", "Υ"},
+	"Phi": {"mi", "Φ"}, "Psi": {"mi", "Ψ"}, "Omega": {"mi", "Ω"},
+
+	// 其它符号
+	"infty": {"mi", "∞"}, "partial": {"mi", "∂"}, "nabla": {"mi", "∇"}, "emptyset": {"mi", "∅"},
+	"ell": {"mi", "ℓ"}, "hbar": {"mi", "ℏ"},
+
+	// 运算符
+	"sum": {"mo", "∑"}, "prod": {"mo", "∏"}, "int": {"mo", "∫"}, "oint": {"mo", "∮"},
+	"pm": {"mo", "±"}, "mp": {"mo", "∓"}, "times": {"mo", "×"}, "div": {"mo", "÷"},
+	"cdot": {"mo", "⋅"}, "cdots": {"mo", "⋯"}, "ldots": {"mo", "…"}, "circ": {"mo", "∘"},
+	"leq": {"mo", "≤"}, "le": {"mo", "≤"}, "geq": {"mo", "≥"}, "ge": {"mo", "≥"},
+	"neq": {"mo", "≠"}, "ne": {"mo", "≠"}, "approx": {"mo", "≈"}, "equiv": {"mo", "≡"},
+	"sim": {"mo", "∼"}, "propto": {"mo", "∝"}, "ll": {"mo", "≪"}, "gg": {"mo", "≫"},
+	"in": {"mo", "∈"}, "notin": {"mo", "∉"}, "subset": {"mo", "⊂"}, "subseteq": {"mo", "⊆"},
+	"supset": {"mo", "⊃"}, "supseteq": {"mo", "⊇"}, "cup": {"mo", "∪"}, "cap": {"mo", "∩"},
+	"forall": {"mo", "∀"}, "exists": {"mo", "∃"}, "neg": {"mo", "¬"}, "land": {"mo", "∧"},
+	"lor": {"mo", "∨"}, "to": {"mo", "→"}, "rightarrow": {"mo", "→"}, "leftarrow": {"mo", "←"},
+	"Rightarrow": {"mo", "⇒"}, "Leftarrow": {"mo", "⇐"}, "leftrightarrow": {"mo", "↔"},
+	"Leftrightarrow": {"mo", "⇔"}, "mapsto": {"mo", "↦"}, "langle": {"mo", "⟨"}, "rangle": {"mo", "⟩"},
+	"{": {"mo", "{"}, "}": {"mo", "}"}, "|": {"mo", "‖"}, "%": {"mo", "%"}, "$": {"mi", "$"},
+	"#": {"mi", "#"}, "&": {"mo", "&"}, "_": {"mi", "_"},
+}
+
+// 以正体显示的函数名
+var texFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true, "tanh": true,
+	"log": true, "ln": true, "lg": true, "exp": true, "lim": true, "max": true, "min": true,
+	"sup": true, "inf": true, "det": true, "gcd": true, "deg": true, "dim": true,
+}
+
+// 空白
+var texSpaces = map[string]string{
+	",": "0.167em", ":": "0.222em", ";": "0.278em", "!": "-0.167em",
+	"quad": "1em", "qquad": "2em",
+}
+
+// 字体
+var texVariants = map[string]string{
+	"mathbf": "bold", "mathit": "italic", "mathrm": "normal",
+	"mathbb": "double-struck", "mathcal": "script", "mathsf": "sans-serif",
+}
+
+// 作为运算符的字符
+const texOperators = "+-=<>/*,;:!|()[]'.?"
+
+type texParser struct {
+	src []rune
+	pos int
+}
+
+func (p *texParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *texParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// 解析一个表达式，直到结束或是遇到 }（group 为 true 时）以及 \right。
+func (p *texParser) parseExpr(group bool) (string, error) {
+	buf := new(bytes.Buffer)
+
+	for p.skipSpace(); !p.eof(); p.skipSpace() {
+		r := p.src[p.pos]
+		if r == '}' {
+			if !group {
+				return "", helper.Errorf("多余的 }")
+			}
+			break
+		}
+		if p.isCommand("right") {
+			break
+		}
+
+		atom, err := p.parseAtom()
+		if err != nil {
+			return "", err
+		}
+
+		if atom, err = p.parseScripts(atom); err != nil {
+			return "", err
+		}
+		buf.WriteString(atom)
+	}
+
+	return buf.String(), nil
+}
+
+// 解析 atom 之后的上下标
+func (p *texParser) parseScripts(atom string) (string, error) {
+	var sub, sup string
+
+	for {
+		p.skipSpace()
+		if p.eof() || (p.src[p.pos] != '_' && p.src[p.pos] != '^') {
+			break
+		}
+
+		r := p.src[p.pos]
+		p.pos++
+		arg, err := p.parseArg()
+		if err != nil {
+			return "", err
+		}
+
+		if r == '_' {
+			if sub != "" {
+				return "", helper.Errorf("重复的下标")
+			}
+			sub = arg
+		} else {
+			if sup != "" {
+				return "", helper.Errorf("重复的上标")
+			}
+			sup = arg
+		}
+	}
+
+	switch {
+	case sub != "" && sup != "":
+		return "<msubsup>" + atom + sub + sup + "</msubsup>", nil
+	case sub != "":
+		return "<msub>" + atom + sub + "</msub>", nil
+	case sup != "":
+		return "<msup>" + atom + sup + "</msup>", nil
+	}
+	return atom, nil
+}
+
+// 解析命令的参数，可以是 {...} 或是单个元素
+func (p *texParser) parseArg() (string, error) {
+	p.skipSpace()
+	if p.eof() {
+		return "", helper.Errorf("缺少参数")
+	}
+
+	if p.src[p.pos] != '{' {
+		return p.parseAtom()
+	}
+
+	p.pos++
+	body, err := p.parseExpr(true)
+	if err != nil {
+		return "", err
+	}
+	// parseExpr 也可能在遇到 \right 时返回
+	if p.eof() || p.src[p.pos] != '}' {
+		return "", helper.Errorf("缺少 }")
+	}
+	p.pos++ // }
+
+	return "<mrow>" + body + "</mrow>", nil
+}
+
+// 读取 {...} 中的原始内容
+func (p *texParser) parseRawArg() (string, error) {
+	p.skipSpace()
+	if p.eof() || p.src[p.pos] != '{' {
+		return "", helper.Errorf("缺少 {")
+	}
+
+	start := p.pos + 1
+	for depth := 0; !p.eof(); p.pos++ {
+		switch p.src[p.pos] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return string(p.src[start : p.pos-1]), nil
+			}
+		}
+	}
+
+	return "", helper.Errorf("缺少 }")
+}
+
+func (p *texParser) parseAtom() (string, error) {
+	r := p.src[p.pos]
+
+	switch {
+	case r == '{':
+		return p.parseArg()
+	case r == '\\':
+		return p.parseCommand()
+	case r == '_' || r == '^':
+		return "", helper.Errorf("%c 之前缺少内容", r)
+	case r == '&' || r == '#' || r == '$' || r == '%':
+		return "", helper.Errorf("不支持的字符 %c", r)
+	case unicode.IsDigit(r):
+		start := p.pos
+		for p.pos++; !p.eof() && (unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '.'); p.pos++ {
+		}
+		return "<mn>" + string(p.src[start:p.pos]) + "</mn>", nil
+	case unicode.IsLetter(r):
+		p.pos++
+		return "<mi>" + html.EscapeString(string(r)) + "</mi>", nil
+	case r == '-':
+		p.pos++
+		return "<mo>−</mo>", nil
+	case r == '\'':
+		p.pos++
+		return "<mo>′</mo>", nil
+	case strings.ContainsRune(texOperators, r):
+		p.pos++
+		return "<mo>" + html.EscapeString(string(r)) + "</mo>", nil
+	}
+
+	return "", helper.Errorf("不支持的字符 %c", r)
+}
+
+// 当前位置是否为指定的命令
+func (p *texParser) isCommand(name string) bool {
+	cmd := `\` + name
+	end := p.pos + len([]rune(cmd))
+	if end > len(p.src) || string(p.src[p.pos:end]) != cmd {
+		return false
+	}
+	return end == len(p.src) || !unicode.IsLetter(p.src[end])
+}
+
+// 读取命令的名称，p.pos 指向 \
+func (p *texParser) readCommand() string {
+	p.pos++ // \
+	if p.eof() {
+		return ""
+	}
+
+	if !unicode.IsLetter(p.src[p.pos]) {
+		p.pos++
+		return string(p.src[p.pos-1])
+	}
+
+	start := p.pos
+	for ; !p.eof() && unicode.IsLetter(p.src[p.pos]); p.pos++ {
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *texParser) parseCommand() (string, error) {
+	name := p.readCommand()
+
+	if s, found := texSymbols[name]; found {
+		return "<" + s.tag + ">" + html.EscapeString(s.text) + "</" + s.tag + ">", nil
+	}
+
+	if texFunctions[name] {
+		return "<mi>" + name + "</mi>", nil
+	}
+
+	if width, found := texSpaces[name]; found {
+		return `<mspace width="` + width + `"/>`, nil
+	}
+
+	if variant, found := texVariants[name]; found {
+		text, err := p.parseRawArg()
+		if err != nil {
+			return "", err
+		}
+		return `<mi mathvariant="` + variant + `">` + html.EscapeString(text) + "</mi>", nil
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac":
+		num, err := p.parseArg()
+		if err != nil {
+			return "", err
+		}
+		den, err := p.parseArg()
+		if err != nil {
+			return "", err
+		}
+		return "<mfrac>" + num + den + "</mfrac>", nil
+	case "sqrt":
+		return p.parseSqrt()
+	case "text", "mbox":
+		text, err := p.parseRawArg()
+		if err != nil {
+			return "", err
+		}
+		return "<mtext>" + html.EscapeString(text) + "</mtext>", nil
+	case "left":
+		return p.parseFence()
+	}
+
+	return "", helper.Errorf(`不支持的命令 \%s`, name)
+}
+
+// \sqrt{x} 或是 \sqrt[n]{x}
+func (p *texParser) parseSqrt() (string, error) {
+	p.skipSpace()
+
+	var index string
+	if !p.eof() && p.src[p.pos] == '[' {
+		end := p.pos + 1
+		for ; end < len(p.src) && p.src[end] != ']'; end++ {
+		}
+		if end >= len(p.src) {
+			return "", helper.Errorf("缺少 ]")
+		}
+
+		sub := &texParser{src: p.src[p.pos+1 : end]}
+		body, err := sub.parseExpr(false)
+		if err != nil {
+			return "", err
+		}
+		index = "<mrow>" + body + "</mrow>"
+		p.pos = end + 1
+	}
+
+	arg, err := p.parseArg()
+	if err != nil {
+		return "", err
+	}
+
+	if index != "" {
+		return "<mroot>" + arg + index + "</mroot>", nil
+	}
+	return "<msqrt>" + arg + "</msqrt>", nil
+}
+
+// \left( ... \right)
+func (p *texParser) parseFence() (string, error) {
+	open, err := p.parseDelimiter()
+	if err != nil {
+		return "", err
+	}
+
+	body, err := p.parseExpr(false)
+	if err != nil {
+		return "", err
+	}
+	if !p.isCommand("right") {
+		return "", helper.Errorf(`\left 缺少对应的 \right`)
+	}
+	p.readCommand()
+
+	closing, err := p.parseDelimiter()
+	if err != nil {
+		return "", err
+	}
+
+	return "<mrow>" + open + body + closing + "</mrow>", nil
+}
+
+// \left 和 \right 之后的分隔符，. 表示空
+func (p *texParser) parseDelimiter() (string, error) {
+	p.skipSpace()
+	if p.eof() {
+		return "", helper.Errorf("缺少分隔符")
+	}
+
+	var text string
+	switch r := p.src[p.pos]; {
+	case r == '.':
+		p.pos++
+		return "", nil
+	case r == '\\':
+		name := p.readCommand()
+		s, found := texSymbols[name]
+		if !found || s.tag != "mo" {
+			return "", helper.Errorf(`无效的分隔符 \%s`, name)
+		}
+		text = s.text
+	case strings.ContainsRune("()[]|/", r):
+		p.pos++
+		text = string(r)
+	default:
+		return "", helper.Errorf("无效的分隔符 %c", r)
+	}
+
+	return `<mo stretchy="true">` + html.EscapeString(text) + "</mo>", nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestContent_renderMath(t *testing.T) {
+	a := assert.New(t)
+
+	render := func(text string) string {
+		c, err := parseContent(text)
+		a.NotError(err).NotNil(c)
+		a.NotError(c.renderMath())
+
+		html, err := c.String()
+		a.NotError(err)
+		return html
+	}
+
+	html := render(`<p>公式 $x^2$ 和 $5 以及 $10</p>`)
+	a.True(strings.HasPrefix(html, `<p>公式 <math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mrow><msup><mi>x</mi><mn>2</mn></msup></mrow>`))
+	a.True(strings.HasSuffix(html, `</math> 和 $5 以及 $10</p>`))
+
+	// 顶层的文本节点，以及块级公式
+	html = render(`$$\frac{1}{2}$$<p>a</p>`)
+	a.True(strings.HasPrefix(html, `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><mrow><mfrac><mrow><mn>1</mn></mrow><mrow><mn>2</mn></mrow></mfrac></mrow>`))
+	a.True(strings.HasSuffix(html, `</math><p>a</p>`))
+
+	// 不需要处理的内容
+	a.Equal(render(`<p>\$x$</p><code>$x$</code><pre>$$x$$</pre>`), `<p>$x$</p><code>$x$</code><pre>$$x$$</pre>`)
+	a.Equal(render(`<p>a &lt; b</p>`), `<p>a &lt; b</p>`)
+
+	// 错误的公式
+	c, err := parseContent(`<p>$\notexists$</p>`)
+	a.NotError(err).NotNil(c)
+	a.Error(c.renderMath())
+}
+
+func TestSplitMath(t *testing.T) {
+	a := assert.New(t)
+
+	type item struct {
+		text, tex string
+		display   bool
+	}
+	split := func(text string) []item {
+		items := []item{}
+		a.NotError(splitMath(text, func(text, tex string, display bool) error {
+			items = append(items, item{text: text, tex: tex, display: display})
+			return nil
+		}))
+		return items
+	}
+
+	a.Equal(split("a $x$ b"), []item{{"a ", "", false}, {"$x$", "x", false}, {" b", "", false}})
+	a.Equal(split("$$ x $$"), []item{{"$$ x $$", "x", true}})
+	a.Equal(split(`$x\$y$`), []item{{`$x\$y$`, `x\$y`, false}})
+
+	// 不是公式
+	a.Equal(split("$5 and $10"), []item{{"$5 and $10", "", false}})
+	a.Equal(split("$ x$"), []item{{"$ x$", "", false}})
+	a.Equal(split("$x $"), []item{{"$x $", "", false}})
+	a.Equal(split(`\$x$`), []item{{"$x$", "", false}})
+	a.Equal(split("$"), []item{{"$", "", false}})
+}
+
+func TestTexToMathML(t *testing.T) {
+	a := assert.New(t)
+
+	body := func(tex string) string {
+		ml, err := texToMathML(tex, false)
+		a.NotError(err)
+
+		start := strings.Index(ml, "<semantics><mrow>") + len("<semantics><mrow>")
+		end := strings.Index(ml, "</mrow><annotation")
+		return ml[start:end]
+	}
+
+	a.Equal(body("a+1.5"), "<mi>a</mi><mo>+</mo><mn>1.5</mn>")
+	a.Equal(body("a-b"), "<mi>a</mi><mo>−</mo><mi>b</mi>")
+	a.Equal(body("x_i^2"), "<msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup>")
+	a.Equal(body("e^{i\\pi}"), "<msup><mi>e</mi><mrow><mi>i</mi><mi>π</mi></mrow></msup>")
+	a.Equal(body("\\sqrt{2}"), "<msqrt><mrow><mn>2</mn></mrow></msqrt>")
+	a.Equal(body("\\sqrt[3]{x}"), "<mroot><mrow><mi>x</mi></mrow><mrow><mn>3</mn></mrow></mroot>")
+	a.Equal(body("\\sin x"), "<mi>sin</mi><mi>x</mi>")
+	a.Equal(body("a\\,b"), `<mi>a</mi><mspace width="0.167em"/><mi>b</mi>`)
+	a.Equal(body("\\mathbb{R}"), `<mi mathvariant="double-struck">R</mi>`)
+	a.Equal(body("\\text{if } x"), "<mtext>if </mtext><mi>x</mi>")
+	a.Equal(body("\\left( x \\right."), `<mrow><mo stretchy="true">(</mo><mi>x</mi></mrow>`)
+	a.Equal(body("\\frac{\\left(a\\right)}{b}"), `<mfrac><mrow><mrow><mo stretchy="true">(</mo><mi>a</mi><mo stretchy="true">)</mo></mrow></mrow><mrow><mi>b</mi></mrow></mfrac>`)
+	a.Equal(body("a < b"), "<mi>a</mi><mo>&lt;</mo><mi>b</mi>")
+
+	ml, err := texToMathML("a<b", true)
+	a.NotError(err)
+	a.True(strings.Contains(ml, ` display="block"`))
+	a.True(strings.HasSuffix(ml, `<annotation encoding="application/x-tex">a&lt;b</annotation></semantics></math>`))
+
+	// 错误的语法
+	for _, tex := range []string{"\\notexists", "x^", "{x", "x}", "^2", "x_1_2", "\\frac{1}", "\\left( x", "{a\\right)}", "\\frac{a\\right)}{b}", "\\sqrt[3{x}", "a & b"} {
+		ml, err := texToMathML(tex, false)
+		a.Error(err, "未正确返回错误：%s", tex).Empty(ml)
+	}
+}
+
+func TestLoad_math(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.True(strings.Contains(d.Posts[1].Content, `<p><math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mrow><mi>E</mi><mo>=</mo>`))
+	a.False(d.Posts[0].Math)
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"html"
+	"html/template"
+)
+
+// Metadata 页面的结构化元数据
+//
+// 包含了 Open Graph、Twitter Card 以及 schema.org 的 JSON-LD 数据，
+// 主题可以通过模板函数 metadata 直接输出所有内容。
+type Metadata struct {
+	OpenGraph []*MetaProperty          // og:* 和 article:* 等属性
+	Twitter   []*MetaProperty          // twitter:* 等属性
+	JSONLD    []map[string]interface{} // 每个元素对应一段 application/ld+json 内容
+}
+
+// MetaProperty 表示 html>head>meta 中的一个键值对
+type MetaProperty struct {
+	Name    string
+	Content string
+}
+
+// AddOpenGraph 添加一条 Open Graph 属性，content 为空时不添加。
+func (m *Metadata) AddOpenGraph(name, content string) {
+	if content == "" {
+		return
+	}
+	m.OpenGraph = append(m.OpenGraph, &MetaProperty{Name: name, Content: content})
+}
+
+// AddTwitter 添加一条 Twitter Card 属性，content 为空时不添加。
+func (m *Metadata) AddTwitter(name, content string) {
+	if content == "" {
+		return
+	}
+	m.Twitter = append(m.Twitter, &MetaProperty{Name: name, Content: content})
+}
+
+// 将元数据输出为 HTML 内容，用于模板函数 metadata
+func renderMetadata(m *Metadata) (template.HTML, error) {
+	if m == nil {
+		return "", nil
+	}
+
+	buf := new(bytes.Buffer)
+
+	for _, p := range m.OpenGraph {
+		buf.WriteString(`<meta property="`)
+		buf.WriteString(html.EscapeString(p.Name))
+		buf.WriteString(`" content="`)
+		buf.WriteString(html.EscapeString(p.Content))
+		buf.WriteString("\" />\n")
+	}
+
+	for _, p := range m.Twitter {
+		buf.WriteString(`<meta name="`)
+		buf.WriteString(html.EscapeString(p.Name))
+		buf.WriteString(`" content="`)
+		buf.WriteString(html.EscapeString(p.Content))
+		buf.WriteString("\" />\n")
+	}
+
+	// json.Marshal 会转义 <、> 和 &，可以安全地放在 script 中
+	for _, ld := range m.JSONLD {
+		bs, err := json.Marshal(ld)
+		if err != nil {
+			return "", err
+		}
+
+		buf.WriteString(`<script type="application/ld+json">`)
+		buf.Write(bs)
+		buf.WriteString("</script>\n")
+	}
+
+	return template.HTML(buf.String()), nil
+}
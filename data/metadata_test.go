@@ -0,0 +1,48 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestMetadata_Add(t *testing.T) {
+	a := assert.New(t)
+
+	m := &Metadata{}
+	m.AddOpenGraph("og:title", "")
+	m.AddTwitter("twitter:title", "")
+	a.Empty(m.OpenGraph).Empty(m.Twitter)
+
+	m.AddOpenGraph("og:title", "title")
+	m.AddTwitter("twitter:title", "title")
+	a.Equal(len(m.OpenGraph), 1).Equal(len(m.Twitter), 1)
+}
+
+func TestRenderMetadata(t *testing.T) {
+	a := assert.New(t)
+
+	html, err := renderMetadata(nil)
+	a.NotError(err).Equal(string(html), "")
+
+	m := &Metadata{}
+	m.AddOpenGraph("og:title", `"title"`)
+	m.AddTwitter("twitter:card", "summary")
+	m.JSONLD = append(m.JSONLD, map[string]interface{}{
+		"@type":    "BlogPosting",
+		"headline": "</script>",
+	})
+
+	html, err = renderMetadata(m)
+	a.NotError(err)
+	str := string(html)
+	a.True(strings.Contains(str, `<meta property="og:title" content="&#34;title&#34;" />`))
+	a.True(strings.Contains(str, `<meta name="twitter:card" content="summary" />`))
+	a.True(strings.Contains(str, `<script type="application/ld+json">`))
+	a.Equal(strings.Count(str, "</script>"), 1) // 内容中的 </script> 被转义
+}
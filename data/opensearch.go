@@ -7,8 +7,6 @@ package data
 import (
 	"net/http"
 
-	"github.com/issue9/web"
-
 	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/data/xmlwriter"
 	"github.com/caixw/gitype/vars"
@@ -47,7 +45,7 @@ func (d *Data) buildOpensearch(conf *loader.Config) error {
 		"method": http.MethodGet,
 		// 需要全链接，否则 Firefox 的搜索框不认。
 		// https://github.com/caixw/gitype/issues/18
-		"template": web.URL(vars.SearchURL("{searchTerms}", 0)),
+		"template": conf.Permalinks.AbsURL(conf.Permalinks.SearchTemplateURL("{searchTerms}")),
 	})
 
 	w.WriteElement("Developer", vars.Name, nil)
@@ -5,10 +5,14 @@
 package data
 
 import (
+	"html/template"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/issue9/logs"
+	"golang.org/x/text/language"
+
 	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/helper"
 	"github.com/caixw/gitype/path"
@@ -26,18 +30,47 @@ type Post struct {
 	Summary   string    // 摘要，同时也作为 meta.description 的内容
 	Content   string    // 内容，同时也作为 outdated 的内容
 	Tags      []*Tag
+	Authors   []*Author // 所有作者，第一个即 Author
+	Series    []*Series // 文章所属的专题以及在专题中的位置
+	Part      int       // 在专题中的序号
 	Outdated  *Outdated
 	State     string
-	Image     string // 封面图片
+	Image     string     // 封面图片
+	Cover     *Image     // 封面图片的详细信息，仅在配置了 images 时才有值
+	Enclosure *Enclosure // 附件，比如播客的音频文件
+	Math      bool       // 是否包含需要转换成 MathML 的公式
+	Aliases   []string   // 文章的其它地址，会以 301 跳转到 Permalink
 	Keywords  string
 
+	// 以下内容根据 Content 计算得到
+	WordCount   int        // 字数
+	ReadingTime int        // 阅读时间，以分钟为单位
+	TOC         []*Heading // 根据 h2~h4 生成的目录
+
 	// 以下内容不存在时，则会使用全局的默认选项
-	Author   *Author
-	License  *Link
-	Template string
-	Language string
+	Author      *Author // 第一作者
+	License     *Link
+	Template    string
+	Language    string
+	LanguageTag language.Tag
 
 	Assets []string
+
+	// 自定义字段，由主题决定如何使用
+	Extra loader.Extra
+
+	// 访问保护，为空表示公开的文章
+	Protection *Protection
+
+	// 相关文章，按相关度从高到低排序，在 data.Load 中计算得到。
+	Related []*Post
+
+	// 其它语言版本中的同一篇文章，根据 translationKey 关联，在 data.Load 中计算得到。
+	Translations []*Post
+
+	permalinks     *Permalinks // 所在网站的地址格式，供转换器等生成地址
+	translationKey string
+	meta           string // 实际加载的 meta.yaml 路径，用于输出错误信息
 }
 
 // Outdated 表示每一篇文章的过时情况
@@ -49,8 +82,10 @@ type Outdated struct {
 	Content string // 自定义的提示内容
 }
 
-func loadPosts(path *path.Path, tags []*Tag, conf *loader.Config) ([]*Post, error) {
-	ps, err := loader.LoadPosts(path)
+// ps 为 loader.LoadPosts 加载的文章中，属于 conf 对应语言的部分；
+// author 为未指定作者的文章使用的默认作者。
+func loadPosts(path *path.Path, tags []*Tag, authors []*Author, author *Author, conf *loader.Config, ps []*loader.Post) ([]*Post, error) {
+	builder, err := newContentBuilder(path, conf)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +99,7 @@ func loadPosts(path *path.Path, tags []*Tag, conf *loader.Config) ([]*Post, erro
 
 		post := &Post{
 			Slug:      p.Slug,
-			Permalink: vars.PostURL(p.Slug),
+			Permalink: conf.Permalinks.PostURL(p.Slug, p.Created),
 			Title:     p.Title,
 			HTMLTitle: helper.ReplaceContent(conf.Pages[vars.PagePost].Title, p.Title),
 			Created:   p.Created,
@@ -73,14 +108,22 @@ func loadPosts(path *path.Path, tags []*Tag, conf *loader.Config) ([]*Post, erro
 			Content:   p.Content,
 			State:     p.State,
 			Image:     p.Image,
+			Enclosure: p.Enclosure,
+			Math:      p.Math,
+			Aliases:   p.Aliases,
+			Part:      p.Part,
 			Keywords:  p.Keywords,
 
-			Author:   p.Author,
 			License:  p.License,
 			Template: p.Template,
 			Language: p.Language,
 
 			Assets: p.Assets,
+			Extra:  p.Extra,
+
+			permalinks:     conf.Permalinks,
+			translationKey: p.TranslationKey,
+			meta:           p.Meta,
 		}
 
 		switch p.Outdated {
@@ -103,19 +146,54 @@ func loadPosts(path *path.Path, tags []*Tag, conf *loader.Config) ([]*Post, erro
 			}
 		}
 
+		// 以前的 slug 按当前的格式转换成完整的地址，其它地址加上网站所在的子目录
+		for index, alias := range post.Aliases {
+			if strings.HasPrefix(alias, "/") {
+				post.Aliases[index] = conf.Permalinks.URL(alias)
+			} else {
+				post.Aliases[index] = conf.Permalinks.PostURL(alias, post.Created)
+			}
+		}
+
+		post.Image = conf.Permalinks.URL(post.Image)
+		if post.Enclosure != nil {
+			post.Enclosure.URL = conf.Permalinks.URL(post.Enclosure.URL)
+		}
+		for index, asset := range post.Assets {
+			post.Assets[index] = conf.Permalinks.URL(asset)
+		}
+
 		if post.Language == "" {
 			post.Language = conf.Language
 		}
-
-		if post.Author == nil {
-			post.Author = conf.Author
+		tag, err := language.Parse(post.Language)
+		if err != nil {
+			return nil, &helper.FieldError{File: post.meta, Message: err.Error(), Field: "language"}
 		}
+		post.LanguageTag = tag
 
 		if post.License == nil {
 			post.License = conf.License
 		}
 
-		if err := attachPostTag(path, post, tags, p.Tags); err != nil {
+		if p.Protection != "" {
+			protection, err := newProtection(conf, post, p.Protection)
+			if err != nil {
+				err.File = post.meta
+				return nil, err
+			}
+			post.Protection = protection
+		}
+
+		if err := attachPostTag(post, tags, p.Tags); err != nil {
+			return nil, err
+		}
+
+		if err := attachPostAuthor(post, p, authors, author); err != nil {
+			return nil, err
+		}
+
+		if err := builder.build(post); err != nil {
 			return nil, err
 		}
 
@@ -127,27 +205,170 @@ func loadPosts(path *path.Path, tags []*Tag, conf *loader.Config) ([]*Post, erro
 	return posts, nil
 }
 
+// 处理文章内容的相关工具，所有文章共用。
+type contentBuilder struct {
+	path         *path.Path
+	shortcodes   *template.Template
+	images       *imageProcessor // 未配置 images 时为空
+	sanitizer    *sanitizer      // 未配置 sanitize 时为空
+	transformers []Transformer
+}
+
+func newContentBuilder(path *path.Path, conf *loader.Config) (*contentBuilder, error) {
+	shortcodes, err := loadShortcodes(path, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	var ip *imageProcessor
+	if conf.Images != nil {
+		ip = newImageProcessor(path, conf.Images, conf.Permalinks)
+	}
+
+	var s *sanitizer
+	if conf.Sanitize != nil {
+		s = newSanitizer(conf.Sanitize)
+	}
+
+	ts, err := loadTransformers(path, conf, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contentBuilder{
+		path:         path,
+		shortcodes:   shortcodes,
+		images:       ip,
+		sanitizer:    s,
+		transformers: ts,
+	}, nil
+}
+
+// 检测文章内容的结构，展开其中的短代码和公式，计算字数、阅读时间和目录，并依次执行所有的转换器。
+//
+// 配置了 sanitize 时，会过滤掉内容中不被允许的元素和属性；
+// 配置了 images 时，还会生成封面图片的不同宽度版本。
+func (b *contentBuilder) build(post *Post) error {
+	if err := validateContent(post.Content); err != nil {
+		return helper.NewFieldError(b.path.PostContentPath(post.Slug), "content", err)
+	}
+
+	text, err := expandShortcodes(b.shortcodes, post)
+	if err != nil {
+		return helper.NewFieldError(b.path.PostContentPath(post.Slug), "content", err)
+	}
+
+	c, err := parseContent(text)
+	if err != nil {
+		return helper.NewFieldError(b.path.PostContentPath(post.Slug), "content", err)
+	}
+
+	if b.sanitizer != nil {
+		c.sanitize(b.sanitizer)
+	}
+
+	if post.Math {
+		if err = c.renderMath(); err != nil {
+			return helper.NewFieldError(b.path.PostContentPath(post.Slug), "content", err)
+		}
+	}
+
+	if b.images != nil {
+		if post.Cover, err = b.images.image(post.Image); err != nil {
+			return helper.NewFieldError(post.meta, "image", err)
+		}
+	}
+
+	// 转换器可能会修改标题的内容，比如 anchors，所以目录需要在转换之前生成。
+	post.WordCount = c.wordCount()
+	post.ReadingTime = readingTime(post.WordCount)
+	post.TOC = c.buildTOC()
+
+	for _, t := range b.transformers {
+		if err = t.Transform(post, c.nodes); err != nil {
+			return helper.NewFieldError(b.path.PostContentPath(post.Slug), "content", err)
+		}
+	}
+
+	post.Content, err = c.String()
+	return err
+}
+
 // 关联文章与标签的相关信息
-func attachPostTag(p *path.Path, post *Post, tags []*Tag, tagString string) *helper.FieldError {
-	ts := strings.Split(tagString, ",")
-	for _, tag := range tags {
-		for _, slug := range ts {
-			if tag.Slug != slug {
+//
+// tagString 中可以使用标签的同义词，不存在的标签只输出警告信息。
+func attachPostTag(post *Post, tags []*Tag, tagString string) *helper.FieldError {
+	for _, slug := range strings.Split(tagString, ",") {
+		tag := findTag(tags, strings.TrimSpace(slug))
+		if tag == nil {
+			err := &helper.FieldError{File: post.meta, Message: "不存在的标签", Field: "tags"}
+			logs.Warn(err.Error(), "：", slug)
+			continue
+		}
+
+		if containsTag(post.Tags, tag) { // 同时指定了标签及其同义词
+			continue
+		}
+
+		post.Tags = append(post.Tags, tag)
+		tag.Posts = append(tag.Posts, post)
+
+		if tag.Modified.Before(post.Modified) {
+			tag.Modified = post.Modified
+		}
+	}
+
+	if len(post.Tags) == 0 {
+		return &helper.FieldError{File: post.meta, Message: "未指定任何关联标签信息", Field: "tags"}
+	}
+
+	return nil
+}
+
+// 根据 translationKey 关联各语言版本中的文章，同一语言中不能有相同的 translationKey。
+func (d *Data) buildTranslations() error {
+	groups := make(map[string][]*Post, len(d.Posts))
+
+	for _, ld := range d.Languages {
+		keys := make(map[string]bool, len(ld.Posts))
+		for _, post := range ld.Posts {
+			key := post.translationKey
+			if key == "" {
 				continue
 			}
 
-			post.Tags = append(post.Tags, tag)
-			tag.Posts = append(tag.Posts, post)
+			if keys[key] {
+				return &helper.FieldError{File: post.meta, Message: "同一语言中存在相同的值", Field: "translationKey"}
+			}
+			keys[key] = true
+
+			groups[key] = append(groups[key], post)
+		}
+	}
 
-			if tag.Modified.Before(post.Modified) {
-				tag.Modified = post.Modified
+	for _, posts := range groups {
+		for _, post := range posts {
+			for _, p := range posts {
+				if p != post {
+					post.Translations = append(post.Translations, p)
+				}
 			}
-			break
 		}
-	} // end for tags
+	}
 
-	if len(post.Tags) == 0 {
-		return &helper.FieldError{File: p.PostMetaPath(post.Slug), Message: "未指定任何关联标签信息", Field: "tags"}
+	return nil
+}
+
+// Translation 获取文章在 d 中的语言版本，d 即为文章所在的语言时，返回文章本身，不存在时返回空值。
+func (post *Post) Translation(d *Data) *Post {
+	if post.permalinks == d.Permalinks {
+		return post
+	}
+
+	for _, t := range post.Translations {
+		if t.permalinks == d.Permalinks {
+			return t
+		}
 	}
 
 	return nil
@@ -0,0 +1,140 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+)
+
+// Protection 表示文章的访问保护
+//
+// 访问受保护的文章需要先输入密码，验证通过之后，
+// 会以 cookie 的形式保存一个由服务端密钥签名的值，同一分组的文章共用该 cookie。
+type Protection struct {
+	Group string // 所属的分组，为空表示文章单独指定了密码
+
+	password string            // 密码的散列值
+	cookie   string            // 保存验证结果的 cookie 名称
+	content  *protectedContent // 从文章中移出的内容
+}
+
+// 受保护文章中不能公开的内容
+type protectedContent struct {
+	summary     string
+	content     string
+	toc         []*Heading
+	wordCount   int
+	readingTime int
+	enclosure   *Enclosure
+}
+
+// value 为文章 meta.yaml 中 protection 的值，可以是分组名称或是密码的散列值。
+func newProtection(conf *loader.Config, post *Post, value string) (*Protection, *helper.FieldError) {
+	p := &Protection{}
+	key := "post:" + post.Slug
+
+	if hash, found := conf.Protections[value]; found {
+		p.Group = value
+		p.password = hash
+		key = "group:" + value
+	} else if loader.IsPasswordHash(value) {
+		p.password = value
+	} else {
+		return nil, &helper.FieldError{Message: "不存在的分组", Field: "protection"}
+	}
+
+	// cookie 的名称中不能包含 / 等字符，所以取其散列值
+	sum := sha256.Sum256([]byte(key))
+	p.cookie = "gitype-" + hex.EncodeToString(sum[:8])
+
+	return p, nil
+}
+
+// CookieName 保存验证结果的 cookie 名称
+func (p *Protection) CookieName() string {
+	return p.cookie
+}
+
+// Check 检测密码是否正确
+func (p *Protection) Check(password string) bool {
+	return loader.CheckPassword(p.password, password)
+}
+
+// Token 生成验证通过之后保存在 cookie 中的值
+//
+// secret 为服务端的密钥，不能出现在公开的数据目录中，否则任何人都可以伪造该值。
+// 签名的内容包含了密码的散列值，修改密码之后，之前的 cookie 自动失效。
+func (p *Protection) Token(secret []byte) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(p.cookie))
+	h.Write([]byte{0})
+	h.Write([]byte(p.password))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify 检测 cookie 中的值是否有效
+func (p *Protection) Verify(secret []byte, token string) bool {
+	return hmac.Equal([]byte(token), []byte(p.Token(secret)))
+}
+
+// 将文章的正文等内容移到 p 中，文章本身只保留标题、地址和时间等公开的信息。
+//
+// 文章会被列表页、相关文章、专题以及其它语言版本等处引用，
+// 这些地方都不应该输出受保护的内容，只有验证通过之后，才能通过 Post.Unlocked 获取。
+func (p *Protection) lock(post *Post) {
+	p.content = &protectedContent{
+		summary:     post.Summary,
+		content:     post.Content,
+		toc:         post.TOC,
+		wordCount:   post.WordCount,
+		readingTime: post.ReadingTime,
+		enclosure:   post.Enclosure,
+	}
+
+	post.Summary = ""
+	post.Content = ""
+	post.TOC = nil
+	post.WordCount = 0
+	post.ReadingTime = 0
+	post.Enclosure = nil
+}
+
+// Unlocked 获取包含完整内容的文章
+//
+// 受保护的文章返回一个包含正文等内容的副本，仅供验证通过之后输出文章页使用；
+// 公开的文章返回其本身。
+func (post *Post) Unlocked() *Post {
+	if post.Protection == nil || post.Protection.content == nil {
+		return post
+	}
+
+	c := post.Protection.content
+	p := *post
+	p.Summary = c.summary
+	p.Content = c.content
+	p.TOC = c.toc
+	p.WordCount = c.wordCount
+	p.ReadingTime = c.readingTime
+	p.Enclosure = c.enclosure
+	return &p
+}
+
+// PublicPosts 过滤掉受保护的文章
+//
+// RSS、Atom、sitemap 和搜索结果等公开的内容，都不应该包含受保护的文章。
+func PublicPosts(posts []*Post) []*Post {
+	ps := make([]*Post, 0, len(posts))
+	for _, post := range posts {
+		if post.Protection == nil {
+			ps = append(ps, post)
+		}
+	}
+	return ps
+}
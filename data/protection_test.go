@@ -0,0 +1,137 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+// 密码 123 的散列值
+const password123 = "pbkdf2-sha256$100000$7dbef8a94ad6a45552eb2916f5b57bb2$906d4d47a7ed873798f7c31cb8ef8d436c6b303c43adc1ad5fc27aa72e129cbf"
+
+func TestNewProtection(t *testing.T) {
+	a := assert.New(t)
+	conf := &loader.Config{
+		Protections: map[string]string{"friends": password123},
+	}
+
+	p1, err := newProtection(conf, &Post{Slug: "p1"}, "friends")
+	a.Nil(err).NotNil(p1)
+	a.Equal(p1.Group, "friends")
+
+	// 同一分组共用 cookie
+	p2, err := newProtection(conf, &Post{Slug: "p2"}, "friends")
+	a.Nil(err).NotNil(p2)
+	a.Equal(p1.CookieName(), p2.CookieName())
+
+	// 单独指定密码
+	p3, err := newProtection(conf, &Post{Slug: "folder/p3"}, password123)
+	a.Nil(err).NotNil(p3)
+	a.Empty(p3.Group).
+		NotEqual(p3.CookieName(), p1.CookieName()).
+		NotContains(p3.CookieName(), "/").
+		True(p3.Check("123"))
+
+	p4, err := newProtection(conf, &Post{Slug: "p4"}, "not-exists")
+	a.NotNil(err).Nil(p4)
+	a.Equal(err.Field, "protection")
+
+	// 不加盐的 SHA256 值
+	p5, err := newProtection(conf, &Post{Slug: "p5"}, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+	a.NotNil(err).Nil(p5)
+}
+
+func TestProtection_Check(t *testing.T) {
+	a := assert.New(t)
+	conf := &loader.Config{
+		Protections: map[string]string{"friends": password123},
+	}
+
+	p, err := newProtection(conf, &Post{Slug: "p1"}, "friends")
+	a.Nil(err).NotNil(p)
+
+	a.True(p.Check("123")).
+		False(p.Check("1234")).
+		False(p.Check(""))
+
+	secret := []byte("secret")
+	token := p.Token(secret)
+	a.NotEmpty(token).
+		True(p.Verify(secret, token)).
+		False(p.Verify(secret, token+"0")).
+		False(p.Verify(secret, ""))
+
+	// 不同的密钥生成的值不同，无法通过公开的数据伪造
+	a.False(p.Verify([]byte("other"), token))
+	a.False(p.Verify(nil, token))
+
+	// 修改密码之后，之前的值失效
+	p.password = "b"
+	a.False(p.Verify(secret, token))
+}
+
+func TestPost_Unlocked(t *testing.T) {
+	a := assert.New(t)
+
+	// 公开的文章
+	post := &Post{Slug: "p1", Content: "<p>content</p>"}
+	a.Equal(post.Unlocked(), post)
+
+	p, err := newProtection(&loader.Config{}, post, password123)
+	a.Nil(err).NotNil(p)
+	post.Protection = p
+	post.Summary = "summary"
+	post.TOC = []*Heading{{Text: "h2"}}
+	post.WordCount = 7
+	post.ReadingTime = 1
+	post.Enclosure = &Enclosure{URL: "/1.mp3"}
+	p.lock(post)
+
+	a.Empty(post.Content).
+		Empty(post.Summary).
+		Empty(post.TOC).
+		Equal(post.WordCount, 0).
+		Equal(post.ReadingTime, 0).
+		Nil(post.Enclosure).
+		Equal(post.Slug, "p1")
+
+	unlocked := post.Unlocked()
+	a.NotEqual(unlocked, post)
+	a.Equal(unlocked.Content, "<p>content</p>").
+		Equal(unlocked.Summary, "summary").
+		Equal(unlocked.TOC, []*Heading{{Text: "h2"}}).
+		Equal(unlocked.WordCount, 7).
+		Equal(unlocked.ReadingTime, 1).
+		Equal(unlocked.Enclosure.URL, "/1.mp3").
+		Equal(unlocked.Protection, post.Protection)
+
+	// 原文章依然不包含内容
+	a.Empty(post.Content)
+}
+
+func TestLoad_protection(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	protected := d.Posts[2]
+	a.Equal(protected.Slug, "protected").
+		NotNil(protected.Protection).
+		Equal(protected.Protection.Group, "friends")
+	a.Equal(PublicPosts(d.Posts), d.Posts[:2])
+
+	// 列表、相关文章等引用的文章不包含受保护的内容
+	a.Empty(protected.Content).Empty(protected.TOC).Equal(protected.WordCount, 0)
+	a.Equal(d.Posts[0].Related[1], protected)
+	a.True(strings.Contains(protected.Unlocked().Content, "internal notes"))
+	a.False(strings.Contains(string(d.RSS.Content), "protected"))
+	for _, archive := range d.Archives {
+		a.NotContains(archive.Posts, protected)
+	}
+}
@@ -11,7 +11,6 @@ import (
 
 	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/data/sw"
-	"github.com/caixw/gitype/vars"
 )
 
 // Manifest 表示 PWA 中的 manifest.json 文件
@@ -94,9 +93,10 @@ func (d *Data) buildSW(conf *loader.Config) error {
 
 	// 首页、archives.html 和 tags.html
 	ver := "gitype-" + strconv.FormatInt(d.Created.Unix(), 10)
-	sw.Add(ver, "/", vars.TagsURL(), vars.ArchivesURL())
+	sw.Add(ver, conf.Permalinks.PostsURL(1), conf.Permalinks.TagsURL(), conf.Permalinks.ArchivesURL())
 
-	for _, post := range d.Posts {
+	// 受保护的文章需要验证之后才能访问，不能预先缓存
+	for _, post := range PublicPosts(d.Posts) {
 		ver = "post-" + strconv.FormatInt(post.Modified.Unix(), 10)
 		sw.Add(ver, post.Permalink)
 		if post.Image != "" {
@@ -125,11 +125,15 @@ func (d *Data) buildSW(conf *loader.Config) error {
 		}
 
 		if !strings.HasPrefix(url, "https://") {
-			url = themeURL(url)
+			url = conf.Permalinks.ThemeURL(url)
 		}
 		sw.Add(ver, url)
 	}
 
+	if d.Highlight != nil {
+		sw.Add("highlight-"+conf.Highlight.Style, d.Highlight.URL)
+	}
+
 	d.ServiceWorker = sw.Bytes()
 	d.ServiceWorkerPath = conf.PWA.ServiceWorker
 
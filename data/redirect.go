@@ -0,0 +1,87 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+)
+
+// 将文章的 aliases 合并到 redirects.yaml 的内容中，
+// 并检测是否与已有的路由冲突，以及是否存在循环跳转。
+func (d *Data) buildRedirects(conf *loader.Config) error {
+	// 记录每一条重定向规则的出处，方便输出错误信息
+	type origin struct {
+		file, field string
+	}
+	origins := make(map[string]*origin, len(d.Redirects))
+
+	for index, r := range d.Redirects {
+		r.Source = d.Permalinks.URL(r.Source)
+		r.Target = d.Permalinks.URL(r.Target)
+
+		origins[r.Source] = &origin{
+			file:  d.path.MetaRedirectsFile,
+			field: "[" + strconv.Itoa(index) + "].source",
+		}
+	}
+
+	for _, post := range d.Posts {
+		for index, alias := range post.Aliases {
+			o := &origin{
+				file:  post.meta,
+				field: "aliases[" + strconv.Itoa(index) + "]",
+			}
+
+			if _, found := origins[alias]; found {
+				return &helper.FieldError{File: o.file, Message: "与其它重定向的地址重复", Field: o.field}
+			}
+			origins[alias] = o
+
+			d.Redirects = append(d.Redirects, &Redirect{
+				Source: alias,
+				Target: post.Permalink,
+				Status: http.StatusMovedPermanently,
+			})
+		}
+	}
+
+	targets := make(map[string]string, len(d.Redirects))
+	for _, r := range d.Redirects {
+		if d.isRoute(r.Source) {
+			o := origins[r.Source]
+			return &helper.FieldError{File: o.file, Message: "与已有的路由冲突", Field: o.field}
+		}
+
+		// 只有站内地址才有可能形成循环
+		if r.Status != http.StatusGone && strings.HasPrefix(r.Target, "/") {
+			targets[r.Source] = r.Target
+		}
+	}
+
+	for _, r := range d.Redirects {
+		visited := map[string]bool{r.Source: true}
+		for target, found := targets[r.Source]; found; target, found = targets[target] {
+			if visited[target] {
+				o := origins[r.Source]
+				return &helper.FieldError{File: o.file, Message: "存在循环跳转", Field: o.field}
+			}
+			visited[target] = true
+		}
+	}
+
+	return nil
+}
+
+// 地址是否与已有的路由冲突，主题和生成的图片目录下的地址也不能使用。
+func (d *Data) isRoute(url string) bool {
+	return d.routes[url] ||
+		strings.HasPrefix(url, d.Permalinks.ThemeURL("")) ||
+		strings.HasPrefix(url, d.Permalinks.ImageURL(""))
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+)
+
+func TestData_buildRedirects(t *testing.T) {
+	a := assert.New(t)
+
+	conf, err := loader.LoadConfig(testdataPath, "")
+	a.NotError(err).NotNil(conf)
+
+	newData := func(redirects ...*Redirect) *Data {
+		d := &Data{
+			path:       testdataPath,
+			Posts:      []*Post{{Slug: "p1", Permalink: "/posts/p1.html", Aliases: []string{"/p1.html"}, meta: testdataPath.PostMetaPath("p1")}},
+			Permalinks: conf.Permalinks,
+			Redirects:  redirects,
+		}
+		a.NotError(d.buildRoutes(conf))
+		return d
+	}
+
+	d := newData(&Redirect{Source: "/old.html", Target: "/p1.html", Status: http.StatusMovedPermanently})
+	a.NotError(d.buildRedirects(nil))
+	a.Equal(len(d.Redirects), 2)
+	a.Equal(d.Redirects[1], &Redirect{Source: "/p1.html", Target: "/posts/p1.html", Status: http.StatusMovedPermanently})
+
+	// 与 aliases 重复
+	d = newData(&Redirect{Source: "/p1.html", Target: "/posts/p1.html", Status: http.StatusFound})
+	err = d.buildRedirects(nil)
+	a.Error(err)
+	ferr, ok := err.(*helper.FieldError)
+	a.True(ok).Equal(ferr.File, testdataPath.PostMetaPath("p1")).Equal(ferr.Field, "aliases[0]")
+
+	// 与文章地址冲突
+	d = newData(&Redirect{Source: "/posts/p1.html", Target: "/index.html", Status: http.StatusMovedPermanently})
+	err = d.buildRedirects(nil)
+	a.Error(err)
+	ferr, ok = err.(*helper.FieldError)
+	a.True(ok).Equal(ferr.File, testdataPath.MetaRedirectsFile).Equal(ferr.Field, "[0].source")
+
+	// 与主题目录冲突
+	d = newData(&Redirect{Source: "/themes/t1/style.css", Status: http.StatusGone})
+	a.Error(d.buildRedirects(nil))
+
+	// 循环跳转
+	d = newData(
+		&Redirect{Source: "/1.html", Target: "/2.html", Status: http.StatusMovedPermanently},
+		&Redirect{Source: "/2.html", Target: "/3.html", Status: http.StatusFound},
+		&Redirect{Source: "/3.html", Target: "/1.html", Status: http.StatusMovedPermanently},
+	)
+	a.Error(d.buildRedirects(nil))
+
+	// 站外地址不会形成循环
+	d = newData(
+		&Redirect{Source: "/1.html", Target: "https://example.com/1.html", Status: http.StatusMovedPermanently},
+		&Redirect{Source: "/2.html", Target: "/1.html", Status: http.StatusMovedPermanently},
+	)
+	a.NotError(d.buildRedirects(nil))
+}
@@ -0,0 +1,160 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+// 用于计算文本相似度的词频向量
+type termVector struct {
+	terms map[string]float64
+	norm  float64
+}
+
+// 计算每一篇文章的相关文章
+//
+// 相关度由以下几部分组成：
+// - 共同的标签，每个标签的权重由其稀有程度决定，关联文章越少的标签权重越高；
+// - 共同的专题，在标签权重的基础上乘以 conf.Related.Series；
+// - 文本相似度，若 conf.Related.Text 不为 0，则以词频向量的余弦相似度乘以该值。
+func (d *Data) buildRelated(conf *loader.Config) error {
+	if conf.Related == nil {
+		return nil
+	}
+	r := conf.Related
+
+	var vectors map[*Post]*termVector
+	if r.Text > 0 {
+		vectors = make(map[*Post]*termVector, len(d.Posts))
+		for _, post := range d.Posts {
+			vectors[post] = newTermVector(stripTags(post.Content))
+		}
+	}
+
+	total := float64(len(d.Posts))
+	for _, post := range d.Posts {
+		scores := make(map[*Post]float64, 10)
+
+		for _, tag := range post.Tags {
+			weight := math.Log(1 + total/float64(len(tag.Posts)))
+			if tag.Series {
+				weight *= *r.Series
+			}
+
+			for _, p := range tag.Posts {
+				if p != post {
+					scores[p] += weight
+				}
+			}
+		}
+
+		if vectors != nil {
+			v := vectors[post]
+			for _, p := range d.Posts {
+				if p == post {
+					continue
+				}
+
+				if sim := v.cosine(vectors[p]); sim > 0 {
+					scores[p] += sim * r.Text
+				}
+			}
+		}
+
+		post.Related = rankRelated(scores, r.Size)
+	}
+
+	return nil
+}
+
+// 按相关度从高到低排序，相关度相同的，按创建时间从新到旧。
+func rankRelated(scores map[*Post]float64, size int) []*Post {
+	posts := make([]*Post, 0, len(scores))
+	for p, score := range scores {
+		if score > 0 {
+			posts = append(posts, p)
+		}
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		si, sj := scores[posts[i]], scores[posts[j]]
+		if si != sj {
+			return si > sj
+		}
+		return posts[i].Created.After(posts[j].Created)
+	})
+
+	if len(posts) > size {
+		posts = posts[:size]
+	}
+	return posts
+}
+
+// 将文本拆分成词频向量。
+func newTermVector(text string) *termVector {
+	v := &termVector{terms: make(map[string]float64, 100)}
+
+	splitWords(text, func(word string) {
+		v.terms[strings.ToLower(word)]++
+	})
+
+	for _, cnt := range v.terms {
+		v.norm += cnt * cnt
+	}
+	v.norm = math.Sqrt(v.norm)
+
+	return v
+}
+
+// 余弦相似度
+func (v *termVector) cosine(v2 *termVector) float64 {
+	if v.norm == 0 || v2.norm == 0 {
+		return 0
+	}
+
+	var dot float64
+	for term, cnt := range v.terms {
+		dot += cnt * v2.terms[term]
+	}
+
+	return dot / (v.norm * v2.norm)
+}
+
+// 将文本拆分成单词，并依次调用 fn。
+//
+// 连续的字母和数字作为一个词，中日韩等文字则每个字都单独作为一个词。
+func splitWords(text string, fn func(word string)) {
+	word := make([]rune, 0, 20)
+	flush := func() {
+		if len(word) > 0 {
+			fn(string(word))
+			word = word[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flush()
+			fn(string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+}
+
+// 是否为中日韩文字，这些文字之间没有空格分隔。
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+func TestData_buildRelated(t *testing.T) {
+	a := assert.New(t)
+
+	now := time.Now()
+	p1 := &Post{Slug: "1", Created: now, Content: "go php java"}
+	p2 := &Post{Slug: "2", Created: now.Add(-time.Hour), Content: "go"}
+	p3 := &Post{Slug: "3", Created: now.Add(-2 * time.Hour), Content: "php"}
+	p4 := &Post{Slug: "4", Created: now.Add(-3 * time.Hour), Content: "go"}
+
+	common := &Tag{Tag: loader.Tag{Slug: "common"}, Posts: []*Post{p1, p2, p3, p4}}
+	rare := &Tag{Tag: loader.Tag{Slug: "rare"}, Posts: []*Post{p1, p3}}
+	series := &Tag{Tag: loader.Tag{Slug: "series", Series: true}, Posts: []*Post{p1, p4}}
+	p1.Tags = []*Tag{common, rare, series}
+	p2.Tags = []*Tag{common}
+	p3.Tags = []*Tag{common, rare}
+	p4.Tags = []*Tag{common, series}
+
+	d := &Data{Posts: []*Post{p1, p2, p3, p4}}
+	weight := 2.0
+	conf := &loader.Config{Related: &loader.Related{Size: 2, Series: &weight}}
+	a.NotError(d.buildRelated(conf))
+
+	// 专题权重最高，其次是较稀有的标签
+	a.Equal(d.Posts[0].Related, []*Post{p4, p3})
+	a.Equal(d.Posts[1].Related, []*Post{p1, p3}) // 权重相同，按时间排序
+
+	// 不计算专题标签的权重
+	weight = 0
+	a.NotError(d.buildRelated(conf))
+	a.Equal(d.Posts[0].Related, []*Post{p3, p2})
+
+	// 文本相似度
+	weight = 2
+	conf.Related.Text = 10
+	a.NotError(d.buildRelated(conf))
+	a.Equal(d.Posts[1].Related, []*Post{p4, p1})
+
+	// 未启用
+	p1.Related = nil
+	a.NotError(d.buildRelated(&loader.Config{}))
+	a.Nil(p1.Related)
+}
+
+func TestNewTermVector(t *testing.T) {
+	a := assert.New(t)
+
+	v := newTermVector("Go go, 中文。go1")
+	a.Equal(v.terms["go"], 2)
+	a.Equal(v.terms["go1"], 1)
+	a.Equal(v.terms["中"], 1)
+	a.Equal(v.terms["文"], 1)
+
+	a.True(math.Abs(v.cosine(v)-1) < 0.0001)
+	a.Equal(v.cosine(newTermVector("")), 0)
+	a.Equal(v.cosine(newTermVector("php")), 0)
+}
+
+func TestLoad_related(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.Equal(d.Posts[0].Related, []*Post{d.Posts[1], d.Posts[2]})
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+)
+
+// 收集所有由程序生成的页面地址，并检测它们之间是否存在冲突。
+//
+// permalinks 中的各个格式在加载时已经检测过，但不同的格式依然有可能生成相同的地址，
+// 比如格式为 /:slug/ 的文章 tags/go 与格式为 /tags/:slug/ 的标签 go。
+func (d *Data) buildRoutes(conf *loader.Config) error {
+	p := conf.Permalinks
+	d.routes = map[string]bool{
+		p.PostsURL(1):      true,
+		p.IndexURL(0):      true,
+		p.TagsURL():        true,
+		p.LinksURL():       true,
+		p.ArchivesURL():    true,
+		p.SearchURL("", 0): true,
+	}
+
+	add := func(url, field string) error {
+		if d.routes[url] {
+			return &helper.FieldError{File: d.path.MetaConfigFile, Message: "地址 %s 与其它页面冲突", Field: field, Args: []interface{}{url}}
+		}
+		d.routes[url] = true
+		return nil
+	}
+
+	for _, post := range d.Posts {
+		if err := add(post.Permalink, "permalinks.post"); err != nil {
+			return err
+		}
+	}
+
+	for _, page := range d.SinglePages {
+		if err := add(page.Permalink, "permalinks.single"); err != nil {
+			return err
+		}
+	}
+
+	for _, tags := range [][]*Tag{d.Tags, d.Series} {
+		for _, tag := range tags {
+			if err := add(tag.Permalink, "permalinks.tag"); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, year := range d.Calendar {
+		if err := add(year.Permalink, "permalinks.archiveYear"); err != nil {
+			return err
+		}
+
+		for _, month := range year.Months {
+			if err := add(month.Permalink, "permalinks.archiveMonth"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(d.Authors) > 0 {
+		if err := add(p.AuthorsURL(), "permalinks.authors"); err != nil {
+			return err
+		}
+	}
+
+	for _, author := range d.Authors {
+		if err := add(author.Permalink, "permalinks.author"); err != nil {
+			return err
+		}
+
+		if author.Feed != nil {
+			if err := add(author.Feed.URL, "permalinks.authorFeed"); err != nil {
+				return err
+			}
+		}
+	}
+
+	feeds := []struct {
+		feed  *Feed
+		field string
+	}{
+		{d.RSS, "rss.url"},
+		{d.Atom, "atom.url"},
+		{d.Sitemap, "sitemap.url"},
+		{d.Opensearch, "opensearch.url"},
+		{d.Manifest, "pwa.manifest.url"},
+		{d.Highlight, "highlight"},
+	}
+	for _, item := range feeds {
+		if item.feed == nil {
+			continue
+		}
+
+		if err := add(item.feed.URL, item.field); err != nil {
+			return err
+		}
+	}
+
+	if d.ServiceWorkerPath != "" {
+		return add(d.ServiceWorkerPath, "pwa.serviceWorker")
+	}
+
+	return nil
+}
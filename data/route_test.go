@@ -0,0 +1,43 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+)
+
+func TestData_buildRoutes(t *testing.T) {
+	a := assert.New(t)
+
+	conf, err := loader.LoadConfig(testdataPath, "")
+	a.NotError(err).NotNil(conf)
+
+	d := &Data{
+		path:  testdataPath,
+		Posts: []*Post{{Slug: "p1", Permalink: "/posts/p1.html"}},
+		Tags:  []*Tag{{Permalink: "/tags/t1.html"}},
+	}
+	a.NotError(d.buildRoutes(conf))
+	a.True(d.routes["/"]).
+		True(d.routes["/posts/p1.html"]).
+		True(d.routes["/tags/t1.html"]).
+		True(d.routes[conf.Permalinks.ArchivesURL()])
+
+	// 文章与标签的地址冲突
+	d.Tags = append(d.Tags, &Tag{Permalink: "/posts/p1.html"})
+	err = d.buildRoutes(conf)
+	a.Error(err)
+	ferr, ok := err.(*helper.FieldError)
+	a.True(ok).Equal(ferr.Field, "permalinks.tag")
+
+	// 与其它页面冲突
+	d.Tags = []*Tag{{Permalink: conf.Permalinks.ArchivesURL()}}
+	a.Error(d.buildRoutes(conf))
+}
@@ -5,11 +5,12 @@
 package data
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/data/xmlwriter"
-	"github.com/issue9/web"
 )
 
 // 生成一个符合 RSS 规范的 XML 文本。
@@ -20,26 +21,34 @@ func (d *Data) buildRSS(conf *loader.Config) error {
 
 	w := xmlwriter.New()
 
-	w.WriteStartElement("rss", map[string]string{
+	attr := map[string]string{
 		"version":    "2.0",
 		"xmlns:atom": "http://www.w3.org/2005/Atom",
-	})
+	}
+	if conf.RSS.Podcast != nil {
+		attr["xmlns:itunes"] = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+	}
+	w.WriteStartElement("rss", attr)
 	w.WriteStartElement("channel", nil)
 
 	w.WriteElement("title", conf.Title, nil)
 	w.WriteElement("description", conf.Subtitle, nil)
-	w.WriteElement("link", web.URL(""), nil)
+	w.WriteElement("link", conf.Permalinks.SiteURL(), nil)
 
 	if conf.Opensearch != nil {
 		w.WriteCloseElement("atom:link", map[string]string{
 			"rel":   "search",
 			"type":  conf.Opensearch.Type,
 			"title": conf.Opensearch.Title,
-			"href":  web.URL(conf.Opensearch.URL),
+			"href":  conf.Permalinks.AbsURL(conf.Opensearch.URL),
 		})
 	}
 
-	addPostsToRSS(w, d)
+	if conf.RSS.Podcast != nil {
+		addPodcastToRSS(w, conf)
+	}
+
+	addPostsToRSS(w, d, conf.RSS.Podcast != nil)
 
 	w.WriteEndElement("channel")
 	w.WriteEndElement("rss")
@@ -58,15 +67,80 @@ func (d *Data) buildRSS(conf *loader.Config) error {
 	return nil
 }
 
-func addPostsToRSS(w *xmlwriter.XMLWriter, d *Data) {
-	for _, p := range d.Posts {
+// 输出 channel 下与 itunes 相关的元素
+func addPodcastToRSS(w *xmlwriter.XMLWriter, conf *loader.Config) {
+	p := conf.RSS.Podcast
+
+	w.WriteElement("language", conf.Language, nil)
+	w.WriteElement("itunes:author", p.Author, nil)
+	w.WriteElement("itunes:summary", conf.Subtitle, nil)
+	w.WriteElement("itunes:explicit", strconv.FormatBool(p.Explicit), nil)
+	w.WriteCloseElement("itunes:image", map[string]string{
+		"href": conf.Permalinks.AbsURL(p.Image),
+	})
+
+	if p.Subcategory == "" {
+		w.WriteCloseElement("itunes:category", map[string]string{
+			"text": p.Category,
+		})
+	} else {
+		w.WriteStartElement("itunes:category", map[string]string{
+			"text": p.Category,
+		})
+		w.WriteCloseElement("itunes:category", map[string]string{
+			"text": p.Subcategory,
+		})
+		w.WriteEndElement("itunes:category")
+	}
+
+	w.WriteStartElement("itunes:owner", nil)
+	w.WriteElement("itunes:name", p.Author, nil)
+	if p.Email != "" {
+		w.WriteElement("itunes:email", p.Email, nil)
+	}
+	w.WriteEndElement("itunes:owner")
+}
+
+// podcast 表示是否需要输出 itunes 相关的元素
+func addPostsToRSS(w *xmlwriter.XMLWriter, d *Data, podcast bool) {
+	for _, p := range PublicPosts(d.Posts) {
 		w.WriteStartElement("item", nil)
 
-		w.WriteElement("link", web.URL(p.Permalink), nil)
+		w.WriteElement("link", d.Permalinks.AbsURL(p.Permalink), nil)
 		w.WriteElement("title", p.Title, nil)
 		w.WriteElement("pubDate", p.Created.Format(time.RFC1123), nil)
 		w.WriteElement("description", p.Summary, nil)
 
+		if p.Enclosure != nil {
+			e := p.Enclosure
+			w.WriteCloseElement("enclosure", map[string]string{
+				"url":    d.Permalinks.AbsURL(e.URL),
+				"type":   e.Type,
+				"length": strconv.FormatInt(e.Length, 10),
+			})
+
+			if podcast {
+				w.WriteElement("guid", d.Permalinks.AbsURL(p.Permalink), nil)
+				if e.Duration > 0 {
+					w.WriteElement("itunes:duration", formatDuration(e.Duration), nil)
+				}
+				if e.Episode > 0 {
+					w.WriteElement("itunes:episode", strconv.Itoa(e.Episode), nil)
+				}
+				w.WriteElement("itunes:explicit", strconv.FormatBool(e.Explicit), nil)
+			}
+		}
+
 		w.WriteEndElement("item")
 	}
 }
+
+// 将时长转换成 itunes:duration 要求的 HH:MM:SS 格式
+func formatDuration(dur time.Duration) string {
+	dur = dur.Round(time.Second)
+	h := dur / time.Hour
+	m := (dur % time.Hour) / time.Minute
+	s := (dur % time.Minute) / time.Second
+
+	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+}
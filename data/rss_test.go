@@ -0,0 +1,36 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestBuildRSS(t *testing.T) {
+	a := assert.New(t)
+	d, err := Load(testdataPath, "")
+	a.NotError(err).NotNil(d)
+
+	a.NotNil(d.RSS)
+	a.True(bytes.Contains(d.RSS.Content, []byte("xmlns:itunes")))
+	a.True(bytes.Contains(d.RSS.Content, []byte("<enclosure ")))
+	a.True(bytes.Contains(d.RSS.Content, []byte("<itunes:duration>01:02:03</itunes:duration>")))
+	a.True(bytes.Contains(d.RSS.Content, []byte("<itunes:episode>2</itunes:episode>")))
+
+	a.True(bytes.Contains(d.Atom.Content, []byte(`"enclosure"`)))
+}
+
+func TestFormatDuration(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(formatDuration(0), "00:00:00")
+	a.Equal(formatDuration(time.Second*61), "00:01:01")
+	a.Equal(formatDuration(time.Hour*25+time.Second*3), "25:00:03")
+	a.Equal(formatDuration(time.Millisecond*1500), "00:00:02")
+}
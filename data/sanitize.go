@@ -0,0 +1,218 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+// 默认允许的元素
+var allowedElements = []string{
+	"a", "abbr", "address", "article", "aside", "audio", "b", "bdi", "bdo", "blockquote", "br",
+	"caption", "cite", "code", "col", "colgroup", "dd", "del", "details", "dfn", "div", "dl", "dt",
+	"em", "figcaption", "figure", "footer", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hr",
+	"i", "img", "ins", "kbd", "li", "main", "mark", "nav", "ol", "p", "picture", "pre", "q",
+	"rp", "rt", "ruby", "s", "samp", "section", "small", "source", "span", "strong", "sub",
+	"summary", "sup", "table", "tbody", "td", "tfoot", "th", "thead", "time", "tr", "track",
+	"u", "ul", "var", "video", "wbr",
+
+	// MathML
+	"math", "semantics", "annotation", "mrow", "mi", "mo", "mn", "mtext", "mspace",
+	"msub", "msup", "msubsup", "mfrac", "msqrt", "mroot", "munder", "mover", "munderover",
+}
+
+// 默认允许的属性，aria- 开头的属性也总是被允许。
+var allowedAttributes = []string{
+	"id", "class", "title", "lang", "dir", "name", "href", "target", "rel",
+	"src", "srcset", "sizes", "alt", "width", "height", "loading", "poster", "controls",
+	"autoplay", "loop", "muted", "preload", "type", "kind", "srclang", "label",
+	"colspan", "rowspan", "span", "start", "reversed", "datetime", "cite", "open",
+	"xmlns", "display", "stretchy", "mathvariant", "encoding",
+}
+
+// 嵌入外部内容的元素，只有 src 指向允许的域名时才会被保留。
+var embedElements = map[string]bool{
+	"iframe": true,
+	"script": true,
+	"embed":  true,
+}
+
+// 嵌入的元素可以额外使用的属性
+var embedAttributes = []string{"allow", "allowfullscreen", "frameborder", "async", "defer"}
+
+// 这些元素不被允许时，连同其内容一起去掉，其它元素只去掉标签本身。
+var dropElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "embed": true, "object": true,
+	"frame": true, "frameset": true, "applet": true, "noscript": true, "template": true,
+	"form": true, "input": true, "button": true, "select": true, "textarea": true,
+	"head": true, "link": true, "meta": true, "base": true, "title": true,
+}
+
+// 包含地址的属性
+var urlAttributes = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"poster": true,
+	"cite":   true,
+}
+
+// 地址中允许使用的协议
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+	"tel":    true,
+}
+
+// 根据 loader.Sanitize 过滤文章的内容
+type sanitizer struct {
+	elements   map[string]bool
+	attributes map[string]bool
+	hosts      map[string]bool
+}
+
+func newSanitizer(conf *loader.Sanitize) *sanitizer {
+	s := &sanitizer{
+		elements:   make(map[string]bool, len(allowedElements)+len(conf.Elements)),
+		attributes: make(map[string]bool, len(allowedAttributes)+len(conf.Attributes)),
+		hosts:      make(map[string]bool, len(conf.Hosts)),
+	}
+
+	for _, list := range [][]string{allowedElements, conf.Elements} {
+		for _, name := range list {
+			s.elements[name] = true
+		}
+	}
+
+	for _, list := range [][]string{allowedAttributes, conf.Attributes} {
+		for _, name := range list {
+			s.attributes[name] = true
+		}
+	}
+
+	for _, host := range conf.Hosts {
+		s.hosts[host] = true
+	}
+
+	return s
+}
+
+// 过滤内容中不被允许的元素和属性，同时也会去掉所有的注释。
+func (c *content) sanitize(s *sanitizer) {
+	root := &html.Node{Type: html.ElementNode, Data: "body"}
+	for _, n := range c.nodes {
+		root.AppendChild(n)
+	}
+
+	s.sanitizeChildren(root)
+
+	c.nodes = c.nodes[:0]
+	for n := root.FirstChild; n != nil; n = root.FirstChild {
+		root.RemoveChild(n)
+		c.nodes = append(c.nodes, n)
+	}
+}
+
+func (s *sanitizer) sanitizeChildren(parent *html.Node) {
+	var next *html.Node
+	for n := parent.FirstChild; n != nil; n = next {
+		next = n.NextSibling
+
+		switch n.Type {
+		case html.CommentNode, html.DoctypeNode:
+			parent.RemoveChild(n)
+		case html.ElementNode:
+			if s.allowElement(n) {
+				s.sanitizeAttributes(n)
+				s.sanitizeChildren(n)
+				continue
+			}
+
+			if dropElements[n.Data] {
+				parent.RemoveChild(n)
+				continue
+			}
+
+			// 只去掉标签，保留其内容
+			s.sanitizeChildren(n)
+			for child := n.FirstChild; child != nil; child = n.FirstChild {
+				n.RemoveChild(child)
+				parent.InsertBefore(child, n)
+			}
+			parent.RemoveChild(n)
+		}
+	}
+}
+
+func (s *sanitizer) allowElement(n *html.Node) bool {
+	if !embedElements[n.Data] {
+		return s.elements[n.Data]
+	}
+
+	// script 只能引用外部的脚本，不能包含内容
+	if n.Data == "script" && n.FirstChild != nil {
+		return false
+	}
+
+	u, err := url.Parse(getAttr(n, "src"))
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "https" || u.Scheme == "http") && s.hosts[strings.ToLower(u.Hostname())]
+}
+
+func (s *sanitizer) sanitizeAttributes(n *html.Node) {
+	attrs := n.Attr[:0]
+	for _, attr := range n.Attr {
+		if s.allowAttribute(n, attr) {
+			attrs = append(attrs, attr)
+		}
+	}
+	n.Attr = attrs
+}
+
+func (s *sanitizer) allowAttribute(n *html.Node, attr html.Attribute) bool {
+	key := strings.ToLower(attr.Key)
+
+	switch {
+	case strings.HasPrefix(key, "on"): // 事件总是被禁止的，即使在配置中指定了
+		return false
+	case strings.HasPrefix(key, "aria-"):
+	case s.attributes[key]:
+	case embedElements[n.Data] && inStrings(key, embedAttributes):
+	default:
+		return false
+	}
+
+	if urlAttributes[key] {
+		return isSafeURL(attr.Val)
+	}
+	return true
+}
+
+// 是否为安全的地址，相对地址或是使用了 allowedSchemes 中协议的地址。
+func isSafeURL(val string) bool {
+	val = strings.TrimSpace(val)
+	index := strings.IndexAny(val, ":/?#")
+	if index < 0 || val[index] != ':' {
+		return true
+	}
+
+	return allowedSchemes[strings.ToLower(val[:index])]
+}
+
+func inStrings(s string, list []string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
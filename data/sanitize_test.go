@@ -0,0 +1,74 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+func TestContent_sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	s := newSanitizer(&loader.Sanitize{
+		Elements:   []string{"form"},
+		Attributes: []string{"style"},
+		Hosts:      []string{"www.youtube.com"},
+	})
+
+	sanitize := func(text string) string {
+		c, err := parseContent(text)
+		a.NotError(err).NotNil(c)
+		c.sanitize(s)
+
+		html, err := c.String()
+		a.NotError(err)
+		return html
+	}
+
+	// 允许的内容
+	text := `<p id="p1" class="c" style="color:red" aria-hidden="true"><a href="https://example.com">a</a><img src="1.png" alt="1"/></p>`
+	a.Equal(sanitize(text), text)
+
+	// 不允许的属性
+	a.Equal(sanitize(`<p onclick="alert(1)" data-x="1">p</p><a href="javascript:alert(1)">a</a><a href="mailto:a@example.com">a</a>`),
+		`<p>p</p><a>a</a><a href="mailto:a@example.com">a</a>`)
+
+	// 不允许的元素
+	a.Equal(sanitize(`<script>alert(1)</script><style>*{}</style><font color="red">text</font><!-- comment --><p>p</p>`),
+		`text<p>p</p>`)
+
+	// 额外允许的元素
+	a.Equal(sanitize(`<form><button>b</button></form>`), `<form></form>`)
+
+	// 嵌入的内容
+	a.Equal(sanitize(`<iframe src="https://www.youtube.com/embed/1" allowfullscreen=""></iframe>`),
+		`<iframe src="https://www.youtube.com/embed/1" allowfullscreen=""></iframe>`)
+	a.Equal(sanitize(`<iframe src="https://example.com/embed/1"></iframe><script src="//www.youtube.com/1.js"></script>`), ``)
+	a.Equal(sanitize(`<script src="https://www.youtube.com/1.js">alert(1)</script>`), ``)
+
+	// 未配置 hosts
+	s = newSanitizer(&loader.Sanitize{})
+	a.Equal(sanitize(`<iframe src="https://www.youtube.com/embed/1"></iframe><p style="color:red">p</p>`), `<p>p</p>`)
+}
+
+func TestIsSafeURL(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(isSafeURL("1.png"))
+	a.True(isSafeURL("/posts/1.png"))
+	a.True(isSafeURL("#id"))
+	a.True(isSafeURL("https://example.com"))
+	a.True(isSafeURL("HTTP://example.com"))
+	a.True(isSafeURL("mailto:a@example.com"))
+	a.True(isSafeURL("posts/a:b.png"))
+
+	a.False(isSafeURL("javascript:alert(1)"))
+	a.False(isSafeURL(" JavaScript:alert(1)"))
+	a.False(isSafeURL("data:text/html,abc"))
+}
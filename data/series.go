@@ -0,0 +1,101 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"sort"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+)
+
+// Series 表示文章在某一专题中的位置信息
+//
+// 与 Page.PrevPage 和 Page.NextPage 不同，
+// Prev 和 Next 仅指向同一专题中的前后文章。
+type Series struct {
+	Tag   *Tag    // 专题
+	Index int     // 当前文章在专题中的位置，从 1 开始
+	Total int     // 专题中的文章总数
+	Posts []*Post // 专题中所有的文章，按顺序排列
+	Prev  *Post   // 专题中的前一篇文章，若是第一篇，则为空
+	Next  *Post   // 专题中的后一篇文章，若是最后一篇，则为空
+}
+
+// 为每一篇文章生成其在专题中的位置信息。
+//
+// 专题中的文章按 part 排序之后保存在 Series.Posts 中，
+// tag.Posts 则保持与其它标签相同的顺序，专题页的文章列表不受影响。
+func (d *Data) buildSeries(conf *loader.Config) error {
+	for _, tag := range d.Series {
+		if err := d.checkSeriesParts(tag); err != nil {
+			return err
+		}
+
+		posts := make([]*Post, len(tag.Posts))
+		copy(posts, tag.Posts)
+		sortSeriesPosts(posts)
+
+		for index, post := range posts {
+			s := &Series{
+				Tag:   tag,
+				Index: index + 1,
+				Total: len(posts),
+				Posts: posts,
+			}
+
+			if index > 0 {
+				s.Prev = posts[index-1]
+			}
+			if index+1 < len(posts) {
+				s.Next = posts[index+1]
+			}
+
+			post.Series = append(post.Series, s)
+		}
+	}
+
+	return nil
+}
+
+// 检测同一专题中是否存在相同的 part 值
+func (d *Data) checkSeriesParts(tag *Tag) error {
+	parts := make(map[int]*Post, len(tag.Posts))
+
+	for _, post := range tag.Posts {
+		if post.Part == 0 {
+			continue
+		}
+
+		if _, found := parts[post.Part]; found {
+			return &helper.FieldError{
+				File:    post.meta,
+				Field:   "part",
+				Message: "在专题 %s 中已经存在相同的序号 %d",
+				Args:    []interface{}{tag.Slug, post.Part},
+			}
+		}
+		parts[post.Part] = post
+	}
+
+	return nil
+}
+
+// 指定了 part 的文章按 part 从小到大排序，未指定的排在最后，按创建时间从旧到新排序。
+func sortSeriesPosts(posts []*Post) {
+	sort.SliceStable(posts, func(i, j int) bool {
+		pi, pj := posts[i].Part, posts[j].Part
+		switch {
+		case pi > 0 && pj > 0:
+			return pi < pj
+		case pi > 0:
+			return true
+		case pj > 0:
+			return false
+		default:
+			return posts[i].Created.Before(posts[j].Created)
+		}
+	})
+}
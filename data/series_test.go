@@ -0,0 +1,50 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+func TestData_buildSeries(t *testing.T) {
+	a := assert.New(t)
+
+	now := time.Now()
+	p1 := &Post{Slug: "1", Created: now, Part: 2}
+	p2 := &Post{Slug: "2", Created: now.Add(-time.Hour)}
+	p3 := &Post{Slug: "3", Created: now.Add(-2 * time.Hour)}
+	p4 := &Post{Slug: "4", Created: now.Add(-3 * time.Hour), Part: 1}
+	tag := &Tag{Tag: loader.Tag{Slug: "series", Series: true}, Posts: []*Post{p1, p2, p3, p4}}
+
+	d := &Data{path: testdataPath, Series: []*Tag{tag}}
+	a.NotError(d.buildSeries(nil))
+	a.Equal(tag.Posts, []*Post{p1, p2, p3, p4}) // 专题页的文章列表不受影响
+	a.Equal(p1.Series[0].Posts, []*Post{p4, p1, p3, p2})
+
+	a.Equal(len(p1.Series), 1)
+	s := p1.Series[0]
+	a.Equal(s.Index, 2).Equal(s.Total, 4).Equal(s.Tag, tag)
+	a.Equal(s.Prev, p4).Equal(s.Next, p3)
+
+	a.Nil(p4.Series[0].Prev)
+	a.Nil(p2.Series[0].Next)
+
+	// 相同的序号
+	p3.Part = 2
+	a.Error(d.buildSeries(nil))
+}
+
+func TestLoad_series(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.Equal(len(d.Series), 1).Equal(d.Series[0].Slug, "series1")
+	a.Equal(d.Posts[1].Series[0].Tag, d.Series[0])
+}
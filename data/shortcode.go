@@ -0,0 +1,288 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+	"github.com/caixw/gitype/vars"
+)
+
+// 短代码的开始和结束标记
+const (
+	shortcodeStart = "{{<"
+	shortcodeEnd   = ">}}"
+
+	// 转义的短代码，{{</* name */>}} 会原样输出为 {{< name >}}
+	shortcodeEscapeStart = shortcodeStart + "/*"
+	shortcodeEscapeEnd   = "*/" + shortcodeEnd
+)
+
+// 内置的短代码，主题可以在 shortcodes 目录下定义同名的模板进行覆盖。
+var builtinShortcodes = map[string]string{
+	"figure": `<figure{{with .Params.class}} class="{{.}}"{{end}}>` +
+		`<img src="{{.Required "src"}}" alt="{{.Params.alt}}"{{with .Params.width}} width="{{.}}"{{end}}{{with .Params.height}} height="{{.}}"{{end}} />` +
+		`{{with .Params.caption}}<figcaption>{{.}}</figcaption>{{end}}</figure>`,
+
+	"video": `<video src="{{.Required "src"}}" controls{{with .Params.poster}} poster="{{.}}"{{end}}{{with .Params.width}} width="{{.}}"{{end}}>{{.Inner}}</video>`,
+
+	"audio": `<audio src="{{.Required "src"}}" controls>{{.Inner}}</audio>`,
+
+	"quote": `<blockquote{{with .Params.cite}} cite="{{.}}"{{end}}>{{.Inner}}` +
+		`{{with .Params.author}}<footer>{{.}}</footer>{{end}}</blockquote>`,
+}
+
+// 文章内容中的短代码
+//
+// 格式为 {{< name key="value" >}}，若后面有对应的 {{< /name >}}，
+// 则两者之间的内容作为 Inner 传递给模板，也可以使用 {{< name />}} 明确表示没有内容。
+type shortcode struct {
+	Name   string
+	Params map[string]string
+	Inner  template.HTML // 开始和结束标签之间的内容，其中的短代码已经被展开
+	Post   *Post
+
+	line  int    // 所在的行号
+	text  string // 普通文本，此时其它字段都为空
+	nodes []*shortcode
+}
+
+// 加载所有的短代码模板
+//
+// 模板名称即为短代码的名称，主题中的短代码模板位于 themes/{id}/shortcodes 目录下，
+// 每个文件表示一个短代码，文件名（不含扩展名）即为短代码的名称。
+func loadShortcodes(path *path.Path, conf *loader.Config) (*template.Template, error) {
+	t := template.New("shortcodes")
+	for name, text := range builtinShortcodes {
+		if _, err := t.New(name).Parse(text); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := filepath.Glob(path.ThemesPath(conf.Theme, vars.ShortcodesFolderName, "*"+vars.TemplateExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		bs, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), vars.TemplateExtension)
+		if _, err = t.New(name).Parse(string(bs)); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// 展开文章内容中的短代码
+func expandShortcodes(t *template.Template, post *Post) (string, error) {
+	if !strings.Contains(post.Content, shortcodeStart) {
+		return post.Content, nil
+	}
+
+	nodes, err := parseShortcodes(post.Content)
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	if err = renderShortcodes(buf, t, post, nodes); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func renderShortcodes(buf *bytes.Buffer, t *template.Template, post *Post, nodes []*shortcode) error {
+	for _, node := range nodes {
+		if node.Name == "" {
+			buf.WriteString(node.text)
+			continue
+		}
+
+		tpl := t.Lookup(node.Name)
+		if tpl == nil {
+			return helper.Errorf("第 %d 行：未定义的短代码 %s", node.line, node.Name)
+		}
+
+		inner := new(bytes.Buffer)
+		if err := renderShortcodes(inner, t, post, node.nodes); err != nil {
+			return err
+		}
+		node.Inner = template.HTML(inner.String())
+		node.Post = post
+
+		if err := tpl.Execute(buf, node); err != nil {
+			return helper.Errorf("第 %d 行：短代码 %s 执行出错：%s", node.line, node.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Required 获取参数 key 的值，若不存在，则返回错误。
+// 供模板中使用，比如 {{.Required "src"}}
+func (sc *shortcode) Required(key string) (string, error) {
+	val, found := sc.Params[key]
+	if !found || val == "" {
+		return "", helper.Errorf("缺少参数 %s", key)
+	}
+	return val, nil
+}
+
+// 将内容解析成由文本和短代码组成的树状结构
+//
+// 未找到结束标签的短代码，其之后的内容依然属于其父元素。
+func parseShortcodes(text string) ([]*shortcode, error) {
+	root := &shortcode{}
+	stack := []*shortcode{root}
+
+	// 将未闭合的短代码之后的内容移至其父元素
+	flatten := func(index int) {
+		for i := len(stack) - 1; i > index; i-- {
+			parent := stack[i-1]
+			parent.nodes = append(parent.nodes, stack[i].nodes...)
+			stack[i].nodes = nil
+		}
+		stack = stack[:index+1]
+	}
+
+	for pos := 0; pos < len(text); {
+		top := stack[len(stack)-1]
+
+		start := strings.Index(text[pos:], shortcodeStart)
+		if start < 0 {
+			top.nodes = append(top.nodes, &shortcode{text: text[pos:]})
+			break
+		}
+		start += pos
+		if start > pos {
+			top.nodes = append(top.nodes, &shortcode{text: text[pos:start]})
+		}
+
+		line := strings.Count(text[:start], "\n") + 1
+
+		// 转义的短代码，作为普通文本输出。
+		// 内容为 HTML，所以 < 和 > 需要转换成实体，以免被当作标签。
+		if strings.HasPrefix(text[start:], shortcodeEscapeStart) {
+			end := strings.Index(text[start:], shortcodeEscapeEnd)
+			if end < 0 {
+				return nil, helper.Errorf("第 %d 行：短代码缺少结束标记 %s", line, shortcodeEscapeEnd)
+			}
+			end += start
+			pos = end + len(shortcodeEscapeEnd)
+
+			inner := text[start+len(shortcodeEscapeStart) : end]
+			top.nodes = append(top.nodes, &shortcode{text: "{{&lt;" + inner + "&gt;}}"})
+			continue
+		}
+
+		end := strings.Index(text[start:], shortcodeEnd)
+		if end < 0 {
+			return nil, helper.Errorf("第 %d 行：短代码缺少结束标记 %s", line, shortcodeEnd)
+		}
+		end += start
+		pos = end + len(shortcodeEnd)
+
+		body := strings.TrimSpace(text[start+len(shortcodeStart) : end])
+
+		// 结束标签
+		if strings.HasPrefix(body, "/") {
+			name := strings.TrimSpace(body[1:])
+			index := len(stack) - 1
+			for ; index > 0 && stack[index].Name != name; index-- {
+			}
+			if index == 0 {
+				return nil, helper.Errorf("第 %d 行：%s 没有对应的开始标签", line, name)
+			}
+
+			flatten(index)
+			stack = stack[:index]
+			continue
+		}
+
+		selfClosing := strings.HasSuffix(body, "/")
+		if selfClosing {
+			body = strings.TrimSpace(strings.TrimSuffix(body, "/"))
+		}
+
+		name, params, err := parseShortcodeParams(body)
+		if err != nil {
+			return nil, helper.Errorf("第 %d 行：%s", line, err)
+		}
+
+		sc := &shortcode{Name: name, Params: params, line: line}
+		top.nodes = append(top.nodes, sc)
+		if !selfClosing {
+			stack = append(stack, sc)
+		}
+	}
+
+	flatten(0)
+	return root.nodes, nil
+}
+
+// 解析 name key1="val1" key2=val2 格式的内容
+func parseShortcodeParams(body string) (name string, params map[string]string, err error) {
+	fields := []rune(body)
+	pos := 0
+
+	skipSpace := func() {
+		for pos < len(fields) && unicode.IsSpace(fields[pos]) {
+			pos++
+		}
+	}
+
+	readUntil := func(fn func(rune) bool) string {
+		start := pos
+		for pos < len(fields) && !fn(fields[pos]) {
+			pos++
+		}
+		return string(fields[start:pos])
+	}
+
+	name = readUntil(unicode.IsSpace)
+	if name == "" {
+		return "", nil, helper.Errorf("缺少短代码名称")
+	}
+
+	params = make(map[string]string, 5)
+	for skipSpace(); pos < len(fields); skipSpace() {
+		key := readUntil(func(r rune) bool { return r == '=' || unicode.IsSpace(r) })
+		if key == "" {
+			return "", nil, helper.Errorf("缺少参数名称")
+		}
+		if pos >= len(fields) || fields[pos] != '=' {
+			return "", nil, helper.Errorf("参数 %s 缺少值", key)
+		}
+		pos++ // =
+
+		if pos < len(fields) && (fields[pos] == '"' || fields[pos] == '\'') {
+			quote := fields[pos]
+			pos++
+			params[key] = readUntil(func(r rune) bool { return r == quote })
+			if pos >= len(fields) {
+				return "", nil, helper.Errorf("参数 %s 的值缺少结束的引号", key)
+			}
+			pos++ // 结束的引号
+		} else {
+			params[key] = readUntil(unicode.IsSpace)
+		}
+	}
+
+	return name, params, nil
+}
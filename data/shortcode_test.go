@@ -0,0 +1,158 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"html/template"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	"golang.org/x/text/language"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+)
+
+func TestLoadShortcodes(t *testing.T) {
+	a := assert.New(t)
+
+	tpl, err := loadShortcodes(testdataPath, &loader.Config{Theme: "t1"})
+	a.NotError(err).NotNil(tpl)
+	a.NotNil(tpl.Lookup("figure"))
+	a.NotNil(tpl.Lookup("gist")) // 由主题定义
+
+	tpl, err = loadShortcodes(testdataPath, &loader.Config{Theme: "t2"})
+	a.NotError(err).NotNil(tpl)
+	a.Nil(tpl.Lookup("gist"))
+}
+
+func TestExpandShortcodes(t *testing.T) {
+	a := assert.New(t)
+
+	tpl, err := loadShortcodes(testdataPath, &loader.Config{Theme: "t1"})
+	a.NotError(err).NotNil(tpl)
+
+	expand := func(content string) (string, error) {
+		return expandShortcodes(tpl, &Post{Slug: "post", Content: content})
+	}
+
+	text, err := expand("<p>abc</p>")
+	a.NotError(err).Equal(text, "<p>abc</p>")
+
+	text, err = expand(`<p>abc</p>{{< figure src="1.png" caption="a < b" >}}`)
+	a.NotError(err).
+		Equal(text, `<p>abc</p><figure><img src="1.png" alt="" /><figcaption>a &lt; b</figcaption></figure>`)
+
+	// 嵌套
+	text, err = expand(`{{< quote author="caixw" >}}<p>{{< audio src="1.mp3" />}}</p>{{< /quote >}}`)
+	a.NotError(err).
+		Equal(text, `<blockquote><p><audio src="1.mp3" controls></audio></p><footer>caixw</footer></blockquote>`)
+
+	// 主题定义的短代码
+	text, err = expand(`{{< gist user=caixw id=1 />}}`)
+	a.NotError(err).
+		Equal(text, `<script src="https://gist.github.com/caixw/1.js"></script>`+"\n")
+
+	// 转义的短代码
+	text, err = expand(`<pre><code>{{</* figure src="1.png" */>}}</code></pre>`)
+	a.NotError(err).
+		Equal(text, `<pre><code>{{&lt; figure src="1.png" &gt;}}</code></pre>`)
+
+	// 未定义的短代码
+	text, err = expand("<p>abc</p>\n{{< not-exists >}}")
+	a.Equal(err.Error(), "第 2 行：未定义的短代码 not-exists").Empty(text)
+	a.Equal(helper.LocalizeError(err, language.English).Error(), "line 2: undefined shortcode not-exists")
+
+	// 缺少参数
+	text, err = expand("\n\n{{< figure >}}")
+	a.Error(err).Empty(text)
+}
+
+func TestParseShortcodes(t *testing.T) {
+	a := assert.New(t)
+
+	nodes, err := parseShortcodes(`a{{< v1 k="v" >}}b{{< v2 >}}c{{< /v1 >}}d`)
+	a.NotError(err).Equal(len(nodes), 3)
+	a.Equal(nodes[0].text, "a")
+	a.Equal(nodes[1].Name, "v1").
+		Equal(nodes[1].Params, map[string]string{"k": "v"}).
+		Equal(len(nodes[1].nodes), 3) // b、v2 和 c
+	a.Equal(nodes[1].nodes[1].Name, "v2").Empty(nodes[1].nodes[1].nodes) // v2 未闭合
+	a.Equal(nodes[2].text, "d")
+
+	// 未闭合的短代码，之后的内容属于其父元素
+	nodes, err = parseShortcodes(`{{< v1 >}}a{{< v1 />}}b`)
+	a.NotError(err).Equal(len(nodes), 4) // v1、a、v1 和 b
+	a.Empty(nodes[0].nodes)
+
+	// 缺少结束标记
+	nodes, err = parseShortcodes("a\n{{< v1 ")
+	a.Equal(err.Error(), "第 2 行：短代码缺少结束标记 >}}").Nil(nodes)
+
+	// 没有开始标签
+	nodes, err = parseShortcodes("{{< v1 >}}{{< /v2 >}}")
+	a.Error(err).Nil(nodes)
+
+	// 转义
+	nodes, err = parseShortcodes(`a{{</* v1 k="v" */>}}b{{</* /v1 */>}}`)
+	a.NotError(err).Equal(len(nodes), 4)
+	a.Empty(nodes[1].Name).Equal(nodes[1].text, `{{&lt; v1 k="v" &gt;}}`)
+	a.Empty(nodes[3].Name).Equal(nodes[3].text, `{{&lt; /v1 &gt;}}`)
+
+	// 转义的短代码缺少结束标记
+	nodes, err = parseShortcodes("a\n{{</* v1 >}}")
+	a.Equal(err.Error(), "第 2 行：短代码缺少结束标记 */>}}").Nil(nodes)
+}
+
+func TestParseShortcodeParams(t *testing.T) {
+	a := assert.New(t)
+
+	name, params, err := parseShortcodeParams(`figure src="a b.png"  alt='中文' width=20`)
+	a.NotError(err).
+		Equal(name, "figure").
+		Equal(params, map[string]string{"src": "a b.png", "alt": "中文", "width": "20"})
+
+	name, params, err = parseShortcodeParams(`figure`)
+	a.NotError(err).Equal(name, "figure").Empty(params)
+
+	_, _, err = parseShortcodeParams(``)
+	a.Error(err)
+
+	_, _, err = parseShortcodeParams(`figure src`)
+	a.Error(err)
+
+	_, _, err = parseShortcodeParams(`figure src="a.png`)
+	a.Error(err)
+
+	_, _, err = parseShortcodeParams(`figure ="a.png"`)
+	a.Error(err)
+}
+
+func TestShortcode_Required(t *testing.T) {
+	a := assert.New(t)
+
+	sc := &shortcode{Params: map[string]string{"src": "1.png", "alt": ""}}
+	val, err := sc.Required("src")
+	a.NotError(err).Equal(val, "1.png")
+
+	_, err = sc.Required("alt")
+	a.Error(err)
+
+	_, err = sc.Required("not-exists")
+	a.Error(err)
+
+	// 作为模板的数据
+	tpl := template.Must(template.New("test").Parse(`{{.Required "src"}}`))
+	a.NotError(tpl.Execute(ioutil.Discard, sc))
+}
+
+func TestLoad_shortcodes(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.True(strings.Contains(d.Posts[0].Content, `<script src="https://gist.github.com/caixw/1.js"></script>`))
+}
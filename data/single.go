@@ -0,0 +1,129 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"strconv"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+	"github.com/caixw/gitype/vars"
+)
+
+// SinglePage 表示 pages 目录下的独立页面
+type SinglePage struct {
+	Slug        string // 唯一名称
+	Permalink   string // 页面的唯一链接
+	Title       string
+	HTMLTitle   string // 网页标题
+	Summary     string // meta.description 的内容
+	Keywords    string
+	Content     string
+	Modified    time.Time
+	Template    string
+	Language    string
+	LanguageTag language.Tag
+}
+
+// ps 为 loader.LoadSinglePages 加载的页面中，属于 conf 对应语言的部分。
+//
+// 页面的内容不会展开短代码，也不会经过转换器处理，但依然会检测其结构，
+// 配置了 sanitize 时，同样会过滤不被允许的元素和属性。
+//
+// pages 目录下没有对应的静态资源路由，内容中的相对地址也不会被转换，
+// 所以页面中只能使用完整的地址引用 raws 或是文章资源目录下的内容。
+func loadSinglePages(path *path.Path, conf *loader.Config, ps []*loader.SinglePage) ([]*SinglePage, error) {
+	var s *sanitizer
+	if conf.Sanitize != nil {
+		s = newSanitizer(conf.Sanitize)
+	}
+
+	pages := make([]*SinglePage, 0, len(ps))
+	for _, p := range ps {
+		page := &SinglePage{
+			Slug:      p.Slug,
+			Permalink: conf.Permalinks.SinglePageURL(p.Slug),
+			Title:     p.Title,
+			HTMLTitle: helper.ReplaceContent(conf.Pages[vars.PageSingle].Title, p.Title),
+			Summary:   p.Summary,
+			Keywords:  p.Keywords,
+			Content:   p.Content,
+			Modified:  p.Modified,
+			Template:  p.Template,
+			Language:  p.Language,
+		}
+
+		if p.Permalink != "" {
+			page.Permalink = conf.Permalinks.URL(p.Permalink)
+		}
+
+		if page.Language == "" {
+			page.Language = conf.Language
+		}
+		tag, err := language.Parse(page.Language)
+		if err != nil {
+			return nil, &helper.FieldError{File: path.PageMetaPath(page.Slug), Message: err.Error(), Field: "language"}
+		}
+		page.LanguageTag = tag
+
+		if err := validateContent(page.Content); err != nil {
+			return nil, helper.NewFieldError(path.PageContentPath(page.Slug), "content", err)
+		}
+
+		if s != nil {
+			c, err := parseContent(page.Content)
+			if err != nil {
+				return nil, helper.NewFieldError(path.PageContentPath(page.Slug), "content", err)
+			}
+			c.sanitize(s)
+			if page.Content, err = c.String(); err != nil {
+				return nil, err
+			}
+		}
+
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// 将菜单中指向独立页面的链接转换成页面的地址
+//
+// 当前语言中不存在该页面时，使用默认语言 main 中的页面。
+func (d *Data) buildMenus(main *Data) error {
+	for index, link := range d.Menus {
+		if link.Page == "" {
+			continue
+		}
+
+		page := findSinglePage(d.SinglePages, link.Page)
+		if page == nil {
+			page = findSinglePage(main.SinglePages, link.Page)
+		}
+		if page == nil {
+			return &helper.FieldError{File: d.path.MetaConfigFile, Message: "不存在的页面", Field: "menus[" + strconv.Itoa(index) + "].page"}
+		}
+
+		link.URL = page.Permalink
+		if link.Text == "" {
+			link.Text = page.Title
+		}
+	}
+
+	return nil
+}
+
+func findSinglePage(pages []*SinglePage, slug string) *SinglePage {
+	for _, page := range pages {
+		if page.Slug == slug {
+			return page
+		}
+	}
+	return nil
+}
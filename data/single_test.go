@@ -0,0 +1,23 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestLoad_singlePages(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.Equal(len(d.SinglePages), 1).
+		Equal(d.SinglePages[0].Permalink, "/pages/privacy.html").
+		Empty(d.Languages[1].SinglePages)
+	a.Equal(d.Menus[2].URL, "/pages/privacy.html").
+		Equal(d.Menus[2].Text, "隐私政策")
+	a.Equal(d.Languages[1].Menus[2].URL, "/pages/privacy.html") // 其它语言使用默认语言的页面
+}
@@ -10,8 +10,6 @@ import (
 
 	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/data/xmlwriter"
-	"github.com/caixw/gitype/vars"
-	"github.com/issue9/web"
 )
 
 // 生成一个符合 sitemap 规范的 XML 文本。
@@ -35,18 +33,35 @@ func (d *Data) buildSitemap(conf *loader.Config) error {
 
 	addPostsToSitemap(w, d, conf)
 
+	// pages 目录下的独立页面
+	for _, page := range d.SinglePages {
+		loc := conf.Permalinks.AbsURL(page.Permalink)
+		addItemToSitemap(w, loc, conf.Sitemap.Changefreq, page.Modified, conf.Sitemap.Priority)
+	}
+
 	// archives.html
-	loc := web.URL(vars.ArchivesURL())
+	loc := conf.Permalinks.AbsURL(conf.Permalinks.ArchivesURL())
 	addItemToSitemap(w, loc, conf.Sitemap.Changefreq, d.Created, conf.Sitemap.Priority)
 
+	// archives/2018.html 和 archives/2018/01.html
+	for _, year := range d.Calendar {
+		periods := append([]*ArchivePeriod{year}, year.Months...)
+		for _, p := range periods {
+			loc = conf.Permalinks.AbsURL(p.Permalink)
+			addItemToSitemap(w, loc, conf.Sitemap.Changefreq, p.Modified, conf.Sitemap.Priority)
+		}
+	}
+
 	// links.html
-	loc = web.URL(vars.LinksURL())
+	loc = conf.Permalinks.AbsURL(conf.Permalinks.LinksURL())
 	addItemToSitemap(w, loc, conf.Sitemap.Changefreq, d.Created, conf.Sitemap.Priority)
 
 	if conf.Sitemap.EnableTag {
 		addTagsToSitemap(w, d, conf)
 	}
 
+	addAuthorsToSitemap(w, d, conf)
+
 	w.WriteEndElement("urlset")
 
 	bs, err := w.Bytes()
@@ -64,8 +79,8 @@ func (d *Data) buildSitemap(conf *loader.Config) error {
 
 func addPostsToSitemap(w *xmlwriter.XMLWriter, d *Data, conf *loader.Config) {
 	sitemap := conf.Sitemap
-	for _, p := range d.Posts {
-		loc := web.URL(p.Permalink)
+	for _, p := range PublicPosts(d.Posts) {
+		loc := conf.Permalinks.AbsURL(p.Permalink)
 		addItemToSitemap(w, loc, sitemap.PostChangefreq, p.Modified, sitemap.PostPriority)
 	}
 }
@@ -73,16 +88,31 @@ func addPostsToSitemap(w *xmlwriter.XMLWriter, d *Data, conf *loader.Config) {
 func addTagsToSitemap(w *xmlwriter.XMLWriter, d *Data, conf *loader.Config) error {
 	sitemap := conf.Sitemap
 
-	loc := web.URL(vars.TagsURL())
+	loc := conf.Permalinks.AbsURL(conf.Permalinks.TagsURL())
 	addItemToSitemap(w, loc, sitemap.Changefreq, d.Created, sitemap.Priority)
 
 	for _, tag := range d.Tags {
-		loc = web.URL(vars.TagURL(tag.Slug, 1))
+		loc = conf.Permalinks.AbsURL(tag.Permalink)
 		addItemToSitemap(w, loc, sitemap.Changefreq, tag.Modified, sitemap.Priority)
 	}
 	return nil
 }
 
+func addAuthorsToSitemap(w *xmlwriter.XMLWriter, d *Data, conf *loader.Config) {
+	if len(d.Authors) == 0 {
+		return
+	}
+	sitemap := conf.Sitemap
+
+	loc := conf.Permalinks.AbsURL(conf.Permalinks.AuthorsURL())
+	addItemToSitemap(w, loc, sitemap.Changefreq, d.Created, sitemap.Priority)
+
+	for _, author := range d.Authors {
+		loc = conf.Permalinks.AbsURL(author.Permalink)
+		addItemToSitemap(w, loc, sitemap.Changefreq, author.Modified, sitemap.Priority)
+	}
+}
+
 func addItemToSitemap(w *xmlwriter.XMLWriter, loc, changefreq string, lastmod time.Time, priority float64) {
 	w.WriteStartElement("url", nil)
 
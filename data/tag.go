@@ -5,6 +5,7 @@
 package data
 
 import (
+	"math"
 	"time"
 
 	"github.com/caixw/gitype/data/loader"
@@ -13,6 +14,9 @@ import (
 	"github.com/caixw/gitype/vars"
 )
 
+// 标签云中权重的级数，Tag.Weight 的取值范围为 [1, tagWeightLevels]
+const tagWeightLevels = 5
+
 // Tag 描述标签信息
 //
 // 标签系统同时包含了标签和专题两个方面，默认情况下为标签，
@@ -21,10 +25,20 @@ type Tag struct {
 	loader.Tag
 
 	HTMLTitle string    // 用于网页的标题
-	Posts     []*Post   // 关联的文章
+	Posts     []*Post   // 关联的文章，包含所有子孙标签的文章
 	Keywords  string    // meta.keywords 标签的内容，如果为空，使用 Tag.Title 属性的值
 	Modified  time.Time // 所有文章中最迟修改的
 	Permalink string    // 唯一链接，指向第一页
+
+	Parent     *Tag   // 父标签，顶级标签为空
+	Children   []*Tag // 子标签，不包含没有文章的标签
+	Breadcrumb []*Tag // 从顶级标签到当前标签的路径，包含当前标签，可用于输出面包屑导航
+
+	// 以下内容为统计数据，在过滤空标签之后计算得到
+	PostCount   int       // 文章数量，即 len(Posts)
+	FirstPosted time.Time // 最早的文章的创建时间
+	LastPosted  time.Time // 最新的文章的创建时间
+	Weight      int       // 在标签云中的权重，从 1 到 5，文章越多，值越大
 }
 
 func loadTags(path *path.Path, conf *loader.Config) ([]*Tag, error) {
@@ -44,7 +58,7 @@ func loadTags(path *path.Path, conf *loader.Config) ([]*Tag, error) {
 		t := &Tag{
 			Tag:       *tag,
 			Posts:     make([]*Post, 0, 100),
-			Permalink: vars.TagURL(tag.Slug, 1),
+			Permalink: conf.Permalinks.TagURL(tag.Slug, 1),
 			Keywords:  keywords,
 			HTMLTitle: helper.ReplaceContent(p.Title, tag.Title),
 			Modified:  conf.Uptime,
@@ -52,9 +66,125 @@ func loadTags(path *path.Path, conf *loader.Config) ([]*Tag, error) {
 		ret = append(ret, t)
 	}
 
+	// 在 loader.LoadTags 中已经检测过父标签是否存在以及是否存在循环引用
+	for _, tag := range ret {
+		if tag.Tag.Parent != "" {
+			tag.Parent = findTag(ret, tag.Tag.Parent)
+			tag.Parent.Children = append(tag.Parent.Children, tag)
+		}
+	}
+	for _, tag := range ret {
+		for t := tag; t != nil; t = t.Parent {
+			tag.Breadcrumb = append([]*Tag{t}, tag.Breadcrumb...)
+		}
+	}
+
 	return ret, nil
 }
 
+// 查找 slug 对应的标签，slug 可以是标签的同义词。
+func findTag(tags []*Tag, slug string) *Tag {
+	for _, tag := range tags {
+		if tag.Slug == slug {
+			return tag
+		}
+
+		for _, synonym := range tag.Synonyms {
+			if synonym == slug {
+				return tag
+			}
+		}
+	}
+
+	return nil
+}
+
+// 将子孙标签的文章合并到各级父标签中，需要在所有文章都关联标签之后调用。
+func mergeTagPosts(tags []*Tag) {
+	direct := make(map[*Tag][]*Post, len(tags))
+	for _, tag := range tags {
+		direct[tag] = tag.Posts
+	}
+
+	for _, tag := range tags {
+		for parent := tag.Parent; parent != nil; parent = parent.Parent {
+			for _, post := range direct[tag] {
+				if containsPost(parent.Posts, post) {
+					continue
+				}
+
+				parent.Posts = append(parent.Posts, post)
+				if parent.Modified.Before(post.Modified) {
+					parent.Modified = post.Modified
+				}
+			}
+		}
+	}
+
+	for _, tag := range tags {
+		sortPosts(tag.Posts)
+	}
+}
+
+func containsTag(tags []*Tag, tag *Tag) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPost(posts []*Post, post *Post) bool {
+	for _, p := range posts {
+		if p == post {
+			return true
+		}
+	}
+	return false
+}
+
+// 计算各个标签的统计数据，标签和专题应该分别计算。
+//
+// 权重按文章数量的对数在最少与最多之间线性分级，
+// 防止少数几个文章特别多的标签，导致其它标签的权重都相同。
+func buildTagStats(tags []*Tag) {
+	if len(tags) == 0 {
+		return
+	}
+
+	min, max := math.MaxInt32, 0
+	for _, tag := range tags {
+		tag.PostCount = len(tag.Posts)
+		if tag.PostCount < min {
+			min = tag.PostCount
+		}
+		if tag.PostCount > max {
+			max = tag.PostCount
+		}
+
+		for _, post := range tag.Posts {
+			if tag.FirstPosted.IsZero() || post.Created.Before(tag.FirstPosted) {
+				tag.FirstPosted = post.Created
+			}
+			if post.Created.After(tag.LastPosted) {
+				tag.LastPosted = post.Created
+			}
+		}
+	}
+
+	for _, tag := range tags {
+		if max == min {
+			tag.Weight = 1
+			continue
+		}
+
+		ratio := (math.Log(float64(tag.PostCount)) - math.Log(float64(min))) /
+			(math.Log(float64(max)) - math.Log(float64(min)))
+		tag.Weight = 1 + int(math.Round(ratio*(tagWeightLevels-1)))
+	}
+}
+
 // 分离标签和专题的列表
 func splitTags(tags []*Tag) (ts []*Tag, series []*Tag) {
 	ts = make([]*Tag, 0, len(tags))
@@ -6,6 +6,7 @@ package data
 
 import (
 	"testing"
+	"time"
 
 	"github.com/caixw/gitype/data/loader"
 	"github.com/issue9/assert"
@@ -23,3 +24,41 @@ func TestSplitTags(t *testing.T) {
 	ts, series := splitTags(tags)
 	a.Equal(len(ts), 2).Equal(len(series), 2)
 }
+
+func TestBuildTagStats(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+	p1 := &Post{Created: now.Add(-time.Hour)}
+	p2 := &Post{Created: now}
+	p3 := &Post{Created: now.Add(-2 * time.Hour)}
+
+	tags := []*Tag{
+		{Posts: []*Post{p1}},
+		{Posts: []*Post{p1, p2}},
+		{Posts: []*Post{p1, p2, p3}},
+	}
+	buildTagStats(tags)
+
+	a.Equal(tags[0].PostCount, 1).Equal(tags[0].Weight, 1)
+	a.Equal(tags[2].PostCount, 3).Equal(tags[2].Weight, tagWeightLevels)
+	a.True(tags[1].Weight > 1).True(tags[1].Weight < tagWeightLevels)
+	a.Equal(tags[2].FirstPosted, p3.Created).Equal(tags[2].LastPosted, p2.Created)
+
+	// 文章数量都相同
+	tags = []*Tag{{Posts: []*Post{p1}}, {Posts: []*Post{p2}}}
+	buildTagStats(tags)
+	a.Equal(tags[0].Weight, 1).Equal(tags[1].Weight, 1)
+}
+
+func TestLoad_tags(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	child := findTag(d.Tags, "golang") // 同义词
+	a.NotNil(child).Equal(child.Slug, "child")
+	a.Equal(child.Parent.Slug, "default1")
+	a.Equal(child.Breadcrumb, []*Tag{child.Parent, child})
+	a.Equal(child.Parent.Children, []*Tag{child})
+	a.Equal(len(child.Parent.Posts), 2) // 包含子标签的文章
+	a.Equal(d.Posts[1].Tags, []*Tag{child, d.Series[0]})
+}
@@ -5,13 +5,17 @@
 package data
 
 import (
-	"fmt"
 	"html/template"
 	"io"
 	"regexp"
+	"strconv"
 	"time"
 
+	"golang.org/x/text/language"
+
 	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/locale"
 	"github.com/caixw/gitype/path"
 	"github.com/caixw/gitype/vars"
 )
@@ -23,6 +27,7 @@ type Theme struct {
 	Template        *template.Template // 当前主题的预编译结果
 	longDateFormat  string             // 长时间的显示格式
 	shortDateFormat string             // 短时间的显示格式
+	locale          *locale.Locale     // 当前语言的翻译内容，包括主题中定义的
 }
 
 // 加载主题
@@ -38,9 +43,35 @@ func loadTheme(path *path.Path, conf *loader.Config) (*Theme, error) {
 		Theme:           *t,
 		longDateFormat:  conf.LongDateFormat,
 		shortDateFormat: conf.ShortDateFormat,
+		locale:          locale.New(conf.LanguageTag, themeMessages(t, conf.LanguageTag)),
 	}, nil
 }
 
+// 从主题的翻译内容中找出与 tag 最接近的语言，都不接近时返回空值。
+func themeMessages(t *loader.Theme, tag language.Tag) map[string]string {
+	if len(t.Messages) == 0 {
+		return nil
+	}
+
+	tags := make([]language.Tag, 0, len(t.Messages))
+	msgs := make([]map[string]string, 0, len(t.Messages))
+	for key, m := range t.Messages {
+		tags = append(tags, language.Make(key)) // 在 loader 中已经检测过
+		msgs = append(msgs, m)
+	}
+
+	_, index, confidence := language.NewMatcher(tags).Match(tag)
+	if confidence == language.No {
+		return nil
+	}
+	return msgs[index]
+}
+
+// T 获取 key 在当前语言中的译文，并以 args 进行格式化，对应模板函数 T。
+func (theme *Theme) T(key string, args ...interface{}) string {
+	return theme.locale.Sprintf(key, args...)
+}
+
 // ExecuteTemplate 渲染指定的模块并输出到 w
 func (d *Data) ExecuteTemplate(w io.Writer, name string, data interface{}) error {
 	return d.Theme.Template.ExecuteTemplate(w, name, data)
@@ -70,7 +101,7 @@ func (d *Data) compileTemplate() error {
 	templates := d.templatesName()
 	for _, tpl := range templates {
 		if nil == d.Theme.Template.Lookup(tpl) {
-			return fmt.Errorf("模板 %s 未定义", tpl)
+			return helper.Errorf("模板 %s 未定义", tpl)
 		}
 	}
 
@@ -86,7 +117,9 @@ func (d *Data) snippetsTemplate() (*template.Template, error) {
 		"ldate":    d.Theme.longDate,
 		"sdate":    d.Theme.shortDate,
 		"rfc3339":  rfc3339Date,
-		"themeURL": themeURL,
+		"themeURL": d.Permalinks.ThemeURL,
+		"metadata": renderMetadata,
+		"T":        d.Theme.T,
 	}
 
 	return template.New("snippets").
@@ -104,9 +137,28 @@ func (d *Data) templatesName() []string {
 		vars.PageTag,
 		vars.PageLinks,
 		vars.PageArchives,
+		vars.PageArchive,
 		vars.PageSearch,
 	}
 
+	if len(d.Authors) > 0 {
+		templates = append(templates, vars.PageAuthors, vars.PageAuthor)
+	}
+
+	for _, post := range d.Posts {
+		if post.Protection != nil {
+			templates = append(templates, vars.PageProtected)
+			break
+		}
+	}
+
+	// 独立页面同样可以自定义模板名称
+	for _, page := range d.SinglePages {
+		if !inStrings(page.Template, templates) {
+			templates = append(templates, page.Template)
+		}
+	}
+
 	// 只有文章页可以自定义模板名称
 	for _, post := range d.Posts {
 		// 默认模板名，肯定已存在于 templates 变量中
@@ -124,10 +176,6 @@ func (d *Data) templatesName() []string {
 	return templates
 }
 
-func themeURL(path string) string {
-	return vars.ThemeURL(path)
-}
-
 func rfc3339Date(t time.Time) interface{} {
 	return t.Format(time.RFC3339)
 }
@@ -157,3 +205,28 @@ var stripExpr = regexp.MustCompile("</?[^</>]+/?>")
 func stripTags(html string) string {
 	return stripExpr.ReplaceAllString(html, "")
 }
+
+// 根据主题中声明的类型，检测 config.yaml、标签和文章中的自定义字段。
+func checkExtra(path *path.Path, conf *loader.Config, theme *Theme, tags []*Tag, posts []*Post) error {
+	if err := theme.Check(loader.ExtraConfig, conf.Extra); err != nil {
+		err.File = path.MetaConfigFile
+		return err
+	}
+
+	for index, tag := range tags {
+		if err := theme.Check(loader.ExtraTag, tag.Extra); err != nil {
+			err.File = path.MetaTagsFile
+			err.Field = "[" + strconv.Itoa(index) + "]." + err.Field
+			return err
+		}
+	}
+
+	for _, post := range posts {
+		if err := theme.Check(loader.ExtraPost, post.Extra); err != nil {
+			err.File = post.meta
+			return err
+		}
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/caixw/gitype/data/loader"
 	"github.com/issue9/assert"
+	"golang.org/x/text/language"
 )
 
 func TestLoadTheme(t *testing.T) {
@@ -20,6 +21,15 @@ func TestLoadTheme(t *testing.T) {
 
 	a.Equal(theme.Name, "name")
 	a.Equal(theme.Author.Name, "caixw")
+	a.Equal(theme.T("阅读全文"), "阅读全文")
+
+	// 主题中的翻译内容
+	conf = &loader.Config{Theme: "t1", LanguageTag: language.MustParse("en-US")}
+	theme, err = loadTheme(testdataPath, conf)
+	a.NotError(err).NotNil(theme)
+	a.Equal(theme.T("阅读全文"), "Read more").
+		Equal(theme.T("标签"), "Topics"). // 覆盖内置的内容
+		Equal(theme.T("归档"), "Archives")
 }
 
 func TestStripTags(t *testing.T) {
@@ -35,3 +45,13 @@ func TestStripTags(t *testing.T) {
 		a.Equal(stripTags(expr), val, "测试[%v]时出错", expr)
 	}
 }
+
+// 自定义字段由 checkExtra 根据主题的定义检测
+func TestLoad_extra(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.Equal(d.Posts[0].Extra["hideComments"], true).Nil(d.Posts[1].Extra)
+	a.Equal(findTag(d.Tags, "default1").Extra["icon"], "star")
+	a.Equal(d.Extra["twitter"], "caixw")
+}
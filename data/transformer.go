@@ -0,0 +1,236 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/caixw/gitype/data/loader"
+	"github.com/caixw/gitype/helper"
+	"github.com/caixw/gitype/path"
+)
+
+// Transformer 文章内容的转换器
+//
+// 在文章加载时，按 config.yaml 中 transformers 指定的顺序，
+// 依次对文章内容解析之后的 HTML 节点进行修改。
+type Transformer interface {
+	Transform(post *Post, nodes []*html.Node) error
+}
+
+// TransformerFunc 将一个函数转换成 Transformer 接口
+type TransformerFunc func(post *Post, nodes []*html.Node) error
+
+// NewTransformerFunc 根据配置项中的参数生成 Transformer 实例
+type NewTransformerFunc func(options map[string]string) (Transformer, error)
+
+// 所有已经注册的转换器
+var transformers = map[string]NewTransformerFunc{
+	"assets":   newAssetsTransformer,
+	"links":    newLinksTransformer,
+	"lazyload": newLazyloadTransformer,
+	"anchors":  newAnchorsTransformer,
+}
+
+// Transform 实现 Transformer 接口
+func (f TransformerFunc) Transform(post *Post, nodes []*html.Node) error {
+	return f(post, nodes)
+}
+
+// RegisterTransformer 注册一个新的转换器
+//
+// 需要在 Load 之前调用，name 为在 config.yaml 中引用的名称，
+// 若该名称已经存在，则返回错误。
+func RegisterTransformer(name string, f NewTransformerFunc) error {
+	if _, found := transformers[name]; found {
+		return helper.Errorf("已经存在同名的转换器：%s", name)
+	}
+
+	transformers[name] = f
+	return nil
+}
+
+// 根据配置内容生成转换器列表
+//
+// 除了 conf.Transformers 中指定的转换器之外，
+// 代码高亮和图片的处理也会作为转换器，添加在列表的最后。
+func loadTransformers(path *path.Path, conf *loader.Config, ip *imageProcessor) ([]Transformer, error) {
+	ts := make([]Transformer, 0, len(conf.Transformers)+2)
+
+	for index, t := range conf.Transformers {
+		field := "transformers[" + strconv.Itoa(index) + "]"
+
+		f, found := transformers[t.Name]
+		if !found {
+			return nil, &helper.FieldError{File: path.MetaConfigFile, Message: "不存在的转换器", Field: field + ".name"}
+		}
+
+		transformer, err := f(t.Options)
+		if err != nil {
+			return nil, helper.NewFieldError(path.MetaConfigFile, field+".options", err)
+		}
+		ts = append(ts, transformer)
+	}
+
+	if conf.Highlight != nil {
+		ts = append(ts, TransformerFunc(func(post *Post, nodes []*html.Node) error {
+			return (&content{nodes: nodes}).highlight()
+		}))
+	}
+
+	if ip != nil && conf.Images.Rewrite {
+		ts = append(ts, TransformerFunc(func(post *Post, nodes []*html.Node) error {
+			return (&content{nodes: nodes}).rewriteImages(ip, post)
+		}))
+	}
+
+	return ts, nil
+}
+
+// 需要转换地址的属性
+var assetAttributes = []string{"src", "href", "poster"}
+
+// assets 将内容中的相对地址转换成以文章资源目录为根目录的绝对地址，
+// 比如文章 folder/post 中的 assets/1.png 会被转换成 /posts/folder/post/assets/1.png。
+func newAssetsTransformer(options map[string]string) (Transformer, error) {
+	return TransformerFunc(func(post *Post, nodes []*html.Node) error {
+		c := &content{nodes: nodes}
+		c.walk(func(n *html.Node) bool {
+			if n.Type != html.ElementNode {
+				return true
+			}
+
+			for _, key := range assetAttributes {
+				if val := getAttr(n, key); isRelativeURL(val) {
+					setAttr(n, key, postAssetURL(post, val))
+				}
+			}
+			return true
+		})
+
+		return nil
+	}), nil
+}
+
+// 是否为相对于当前目录的地址，以 / 开头、带协议或是仅包含锚点的都不算。
+func isRelativeURL(u string) bool {
+	if u == "" || u[0] == '/' || u[0] == '#' || u[0] == '?' {
+		return false
+	}
+
+	index := strings.IndexAny(u, ":/?#")
+	return index < 0 || u[index] != ':'
+}
+
+// links 为外部链接添加 rel 和 target 属性
+//
+// 可用的参数：
+// - rel 添加到 rel 属性中的值，默认为 noopener nofollow；
+// - target 链接的 target 属性，默认为 _blank，若链接已经指定了 target，则不作修改。
+func newLinksTransformer(options map[string]string) (Transformer, error) {
+	rel, found := options["rel"]
+	if !found {
+		rel = "noopener nofollow"
+	}
+
+	target, found := options["target"]
+	if !found {
+		target = "_blank"
+	}
+
+	return TransformerFunc(func(post *Post, nodes []*html.Node) error {
+		site, err := url.Parse(post.permalinks.SiteURL())
+		if err != nil {
+			return err
+		}
+
+		c := &content{nodes: nodes}
+		c.walk(func(n *html.Node) bool {
+			if n.Type != html.ElementNode || n.DataAtom != atom.A {
+				return true
+			}
+
+			u, err := url.Parse(getAttr(n, "href"))
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == site.Host {
+				return true
+			}
+
+			for _, token := range strings.Fields(rel) {
+				addToken(n, "rel", token)
+			}
+			if target != "" && getAttr(n, "target") == "" {
+				setAttr(n, "target", target)
+			}
+			return true
+		})
+
+		return nil
+	}), nil
+}
+
+// lazyload 为图片和 iframe 添加 loading="lazy" 属性
+func newLazyloadTransformer(options map[string]string) (Transformer, error) {
+	return TransformerFunc(func(post *Post, nodes []*html.Node) error {
+		c := &content{nodes: nodes}
+		c.walk(func(n *html.Node) bool {
+			if n.Type == html.ElementNode && (n.DataAtom == atom.Img || n.DataAtom == atom.Iframe) {
+				lazyload(n)
+			}
+			return true
+		})
+
+		return nil
+	}), nil
+}
+
+// 若未指定 loading 属性，则将其设置为 lazy
+func lazyload(n *html.Node) {
+	if getAttr(n, "loading") == "" {
+		setAttr(n, "loading", "lazy")
+	}
+}
+
+// anchors 在 h2~h4 标题的末尾添加指向自身的链接
+//
+// 可用的参数：
+// - text 链接的文本，默认为 #；
+// - class 链接的 class 属性，默认为 anchor。
+func newAnchorsTransformer(options map[string]string) (Transformer, error) {
+	text, found := options["text"]
+	if !found {
+		text = "#"
+	}
+
+	class, found := options["class"]
+	if !found {
+		class = "anchor"
+	}
+
+	return TransformerFunc(func(post *Post, nodes []*html.Node) error {
+		c := &content{nodes: nodes}
+		c.headings(func(n *html.Node, level int, id string) {
+			a := &html.Node{
+				Type:     html.ElementNode,
+				Data:     "a",
+				DataAtom: atom.A,
+				Attr:     []html.Attribute{{Key: "href", Val: "#" + id}},
+			}
+			if class != "" {
+				setAttr(a, "class", class)
+			}
+			setAttr(a, "aria-hidden", "true")
+			a.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+
+			n.AppendChild(a)
+		})
+
+		return nil
+	}), nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	"golang.org/x/net/html"
+
+	"github.com/caixw/gitype/data/loader"
+)
+
+// 使用 name 指定的转换器转换 text
+func transform(a *assert.Assertion, name string, options map[string]string, text string) string {
+	t, err := transformers[name](options)
+	a.NotError(err).NotNil(t)
+
+	conf, err := loader.LoadConfig(testdataPath, "")
+	a.NotError(err).NotNil(conf)
+
+	c, err := parseContent(text)
+	a.NotError(err).NotNil(c)
+	a.NotError(t.Transform(&Post{Slug: "folder/post", permalinks: conf.Permalinks}, c.nodes))
+
+	html, err := c.String()
+	a.NotError(err)
+	return html
+}
+
+func TestRegisterTransformer(t *testing.T) {
+	a := assert.New(t)
+
+	f := func(options map[string]string) (Transformer, error) {
+		return TransformerFunc(func(*Post, []*html.Node) error { return nil }), nil
+	}
+	a.Error(RegisterTransformer("assets", f))
+	a.NotError(RegisterTransformer("test-transformer", f))
+	a.Error(RegisterTransformer("test-transformer", f))
+	delete(transformers, "test-transformer")
+}
+
+func TestLoadTransformers(t *testing.T) {
+	a := assert.New(t)
+
+	conf := &loader.Config{
+		Transformers: []*loader.Transformer{{Name: "assets"}, {Name: "anchors"}},
+	}
+	ts, err := loadTransformers(testdataPath, conf, nil)
+	a.NotError(err).Equal(len(ts), 2)
+
+	// 代码高亮
+	conf.Highlight = &loader.Highlight{Style: "monokai"}
+	ts, err = loadTransformers(testdataPath, conf, nil)
+	a.NotError(err).Equal(len(ts), 3)
+
+	// 不存在的转换器
+	conf.Transformers = append(conf.Transformers, &loader.Transformer{Name: "not-exists"})
+	ts, err = loadTransformers(testdataPath, conf, nil)
+	a.Error(err).Nil(ts)
+}
+
+func TestAssetsTransformer(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(transform(a, "assets", nil, `<img src="assets/1.png"/><a href="../post2.html#p1">post</a>`),
+		`<img src="/posts/folder/post/assets/1.png"/><a href="/posts/folder/post2.html#p1">post</a>`)
+
+	// 不需要转换的地址
+	text := `<img src="/1.png"/><a href="#p1">p1</a><a href="https://example.com">a</a><a href="mailto:a@example.com">a</a>`
+	a.Equal(transform(a, "assets", nil, text), text)
+}
+
+func TestIsRelativeURL(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(isRelativeURL("1.png"))
+	a.True(isRelativeURL("assets/1.png"))
+	a.True(isRelativeURL("../1.png"))
+	a.True(isRelativeURL("assets/a:b.png"))
+
+	a.False(isRelativeURL(""))
+	a.False(isRelativeURL("/1.png"))
+	a.False(isRelativeURL("#id"))
+	a.False(isRelativeURL("?page=1"))
+	a.False(isRelativeURL("https://example.com"))
+	a.False(isRelativeURL("data:image/png;base64,abc"))
+}
+
+func TestLinksTransformer(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(transform(a, "links", nil, `<a href="https://example.com" rel="author">a</a><a href="/posts/1.html">1</a>`),
+		`<a href="https://example.com" rel="author noopener nofollow" target="_blank">a</a><a href="/posts/1.html">1</a>`)
+
+	a.Equal(transform(a, "links", map[string]string{"rel": "noopener", "target": ""}, `<a href="http://example.com" target="_self">a</a>`),
+		`<a href="http://example.com" target="_self" rel="noopener">a</a>`)
+}
+
+func TestLazyloadTransformer(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(transform(a, "lazyload", nil, `<img src="1.png"/><iframe src="1.html"></iframe><img src="2.png" loading="eager"/>`),
+		`<img src="1.png" loading="lazy"/><iframe src="1.html" loading="lazy"></iframe><img src="2.png" loading="eager"/>`)
+}
+
+func TestAnchorsTransformer(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(transform(a, "anchors", nil, `<h2>Go 语言</h2><h3 id="h3">h3</h3><h5>h5</h5>`),
+		`<h2 id="go-语言">Go 语言<a href="#go-语言" class="anchor" aria-hidden="true">#</a></h2><h3 id="h3">h3<a href="#h3" class="anchor" aria-hidden="true">#</a></h3><h5>h5</h5>`)
+
+	a.Equal(transform(a, "anchors", map[string]string{"text": "¶", "class": ""}, `<h2>h2</h2>`),
+		`<h2 id="h2">h2<a href="#h2" aria-hidden="true">¶</a></h2>`)
+}
+
+func TestLoad_transformers(t *testing.T) {
+	a := assert.New(t)
+	d := loadTestdata(a)
+
+	a.True(strings.Contains(d.Posts[1].Content, `<img src="/posts/folder/post2/assets/cover.png" alt="cover" loading="lazy" srcset="`))
+}
@@ -15,9 +15,6 @@ type Feed struct {
 }
 
 type (
-	// Author 描述作者信息
-	Author = loader.Author
-
 	// Link 描述链接的内容
 	Link = loader.Link
 
@@ -26,4 +23,13 @@ type (
 
 	// Page 配置配置
 	Page = loader.Page
+
+	// Enclosure 文章的附件
+	Enclosure = loader.Enclosure
+
+	// Redirect 重定向规则
+	Redirect = loader.Redirect
+
+	// Permalinks 各类页面地址的格式
+	Permalinks = loader.Permalinks
 )
@@ -0,0 +1,107 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/caixw/gitype/helper"
+)
+
+// 空元素，不需要结束标签
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// 可以省略结束标签的元素
+var optionalEndElements = map[string]bool{
+	"p": true, "li": true, "dt": true, "dd": true, "tr": true, "td": true, "th": true,
+	"thead": true, "tbody": true, "tfoot": true, "option": true, "optgroup": true,
+	"colgroup": true, "caption": true, "rb": true, "rt": true, "rtc": true, "rp": true,
+}
+
+// 可以使用自闭合语法的外部元素
+var foreignElements = map[string]bool{
+	"math": true,
+	"svg":  true,
+}
+
+type openElement struct {
+	name string
+	line int
+}
+
+// 检测 HTML 内容的结构是否正确
+//
+// HTML 的解析器会自动修正错误的结构，但是修正之后的结果往往与预期不同，
+// 比如一个未闭合的 div 会导致整个页面的布局错乱，所以在加载时需要明确地报错。
+func validateContent(text string) error {
+	z := html.NewTokenizer(strings.NewReader(text))
+	stack := make([]*openElement, 0, 10)
+	foreign := 0 // 处于 math 或是 svg 元素中
+	line := 1
+
+	for {
+		typ := z.Next()
+		current := line
+		line += bytes.Count(z.Raw(), []byte{'\n'})
+
+		switch typ {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return helper.Errorf("第 %d 行：%s", current, err)
+			}
+
+			for i := len(stack) - 1; i >= 0; i-- {
+				if !optionalEndElements[stack[i].name] {
+					return helper.Errorf("第 %d 行：元素 <%s> 未闭合", stack[i].line, stack[i].name)
+				}
+			}
+			return nil
+		case html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			if !voidElements[string(name)] && foreign == 0 && !foreignElements[string(name)] {
+				return helper.Errorf("第 %d 行：元素 <%s> 不能自闭合", current, name)
+			}
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if voidElements[string(name)] {
+				continue
+			}
+
+			stack = append(stack, &openElement{name: string(name), line: current})
+			if foreignElements[string(name)] {
+				foreign++
+			}
+		case html.EndTagToken:
+			bs, _ := z.TagName()
+			name := string(bs)
+
+			index := len(stack) - 1
+			for ; index >= 0 && stack[index].name != name; index-- {
+			}
+			if index < 0 {
+				return helper.Errorf("第 %d 行：多余的结束标签 </%s>", current, name)
+			}
+
+			for i := len(stack) - 1; i > index; i-- {
+				if !optionalEndElements[stack[i].name] {
+					return helper.Errorf("第 %d 行：元素 <%s> 未闭合", stack[i].line, stack[i].name)
+				}
+			}
+
+			stack = stack[:index]
+			if foreignElements[name] {
+				foreign--
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package data
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestValidateContent(t *testing.T) {
+	a := assert.New(t)
+
+	a.NotError(validateContent(`<article><h1>title</h1><p>p1<p>p2<br><img src="1.png" /></article>`))
+	a.NotError(validateContent(`<ul><li>1<li>2</ul><table><tr><td>1<td>2</table>`))
+	a.NotError(validateContent(`<script>if (a < b && c > d) {}</script>`))
+	a.NotError(validateContent(`<math><mspace width="1em"/></math>`))
+	a.NotError(validateContent(`{{< figure src="1.png" >}}{{< quote >}}text{{< /quote >}}`))
+
+	err := validateContent("<article>\n<div>\n<p>p</p>\n</article>")
+	a.Equal(err.Error(), "第 2 行：元素 <div> 未闭合")
+
+	err = validateContent("<div>\n</div>\n</div>")
+	a.Equal(err.Error(), "第 3 行：多余的结束标签 </div>")
+
+	err = validateContent("<article>\n\n<section>")
+	a.Equal(err.Error(), "第 3 行：元素 <section> 未闭合")
+
+	err = validateContent("<p>\n<div />")
+	a.Equal(err.Error(), "第 2 行：元素 <div> 不能自闭合")
+
+	// 属性中的换行
+	err = validateContent("<img\nsrc=\"1.png\">\n<span>")
+	a.Equal(err.Error(), "第 3 行：元素 <span> 未闭合")
+}
@@ -1,6 +1,9 @@
 module github.com/caixw/gitype
 
 require (
+	github.com/alecthomas/chroma v0.6.0
+	github.com/danwakefield/fnmatch v0.0.0-20160403171240-cbb64ac3d964 // indirect
+	github.com/dlclark/regexp2 v1.1.6 // indirect
 	github.com/fsnotify/fsnotify v1.4.7 // indirect
 	github.com/issue9/assert v1.0.0
 	github.com/issue9/is v1.0.0
@@ -9,6 +12,8 @@ require (
 	github.com/issue9/utils v1.0.0
 	github.com/issue9/version v1.0.0
 	github.com/issue9/web v0.16.2
+	golang.org/x/image v0.0.0-20180708004352-c73c2afc3b81
+	golang.org/x/net v0.0.0-20180906233101-161cd47e91fd
 	golang.org/x/sys v0.0.0-20180905080454-ebe1bf3edb33 // indirect
 	golang.org/x/text v0.3.0
 	gopkg.in/fsnotify.v1 v1.4.7
@@ -16,6 +21,8 @@ require (
 )
 
 replace (
+	golang.org/x/image => github.com/golang/image v0.0.0-20180708004352-c73c2afc3b81
+	golang.org/x/net => github.com/golang/net v0.0.0-20180906233101-161cd47e91fd
 	golang.org/x/sys => github.com/golang/sys v0.0.0-20180905080454-ebe1bf3edb33
 	golang.org/x/text => github.com/golang/text v0.3.0
 )
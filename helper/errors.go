@@ -4,15 +4,89 @@
 
 package helper
 
-import "fmt"
+import (
+	"golang.org/x/text/language"
+
+	"github.com/caixw/gitype/locale"
+)
 
 // FieldError 表示加载文件出错时的具体的错误信息
 type FieldError struct {
 	File    string // 所在文件
-	Message string // 错误信息
+	Message string // 错误信息，以简体中文表示，输出时会被翻译成 Language 指定的语言
 	Field   string // 所在的字段
+
+	// Message 的格式化参数，此时 Message 为带格式的翻译键名，比如 "类型必须为 %s"。
+	// 参数中的 *Error 和 *FieldError 会以相同的语言输出。
+	Args []interface{}
+
+	// 错误信息的语言，一般为 config.yaml 中的 language，为空时使用简体中文。
+	Language language.Tag
+}
+
+// Error 可翻译的错误信息
+//
+// 需要带参数的错误信息，不能将参数直接拼接到错误信息中，
+// 否则无法找到对应的翻译内容，应该使用 Errorf 将格式和参数分开保存。
+type Error struct {
+	Format string // 以简体中文表示的格式，同时也是翻译内容的键名
+	Args   []interface{}
+
+	// 错误信息的语言，为空时使用简体中文。
+	Language language.Tag
+}
+
+// Errorf 声明一个可翻译的错误信息，format 为翻译内容的键名。
+func Errorf(format string, args ...interface{}) *Error {
+	return &Error{Format: format, Args: args}
+}
+
+// NewFieldError 将 err 转换成 FieldError
+//
+// err 为 *Error 时，保留其格式和参数，输出时可以被正确地翻译。
+func NewFieldError(file, field string, err error) *FieldError {
+	if e, ok := err.(*Error); ok {
+		return &FieldError{File: file, Message: e.Format, Field: field, Args: e.Args}
+	}
+	return &FieldError{File: file, Message: err.Error(), Field: field}
+}
+
+func (err *Error) Error() string {
+	return locale.Sprintf(err.Language, err.Format, localizeArgs(err.Language, err.Args)...)
 }
 
 func (err *FieldError) Error() string {
-	return fmt.Sprintf("在文件 %s 中的 %s 字段发生错误：%s", err.File, err.Field, err.Message)
+	msg := locale.Sprintf(err.Language, err.Message, localizeArgs(err.Language, err.Args)...)
+	return locale.Sprintf(err.Language, "在文件 %s 中的 %s 字段发生错误：%s", err.File, err.Field, msg)
+}
+
+// 将 args 中的错误信息以 tag 语言输出
+func localizeArgs(tag language.Tag, args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return args
+	}
+
+	ret := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			arg = LocalizeError(err, tag).Error()
+		}
+		ret = append(ret, arg)
+	}
+	return ret
+}
+
+// LocalizeError 若 err 为未指定语言的 FieldError 或是 Error，则将其语言设置为 tag。
+func LocalizeError(err error, tag language.Tag) error {
+	switch e := err.(type) {
+	case *FieldError:
+		if e.Language == language.Und {
+			e.Language = tag
+		}
+	case *Error:
+		if e.Language == language.Und {
+			e.Language = tag
+		}
+	}
+	return err
 }
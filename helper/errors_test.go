@@ -4,4 +4,77 @@
 
 package helper
 
-var _ error = &FieldError{}
+import (
+	"errors"
+	"testing"
+
+	"github.com/issue9/assert"
+	"golang.org/x/text/language"
+)
+
+var (
+	_ error = &FieldError{}
+	_ error = &Error{}
+)
+
+func TestFieldError_Error(t *testing.T) {
+	a := assert.New(t)
+
+	err := &FieldError{File: "config.yaml", Field: "title", Message: "不能为空"}
+	a.Equal(err.Error(), "在文件 config.yaml 中的 title 字段发生错误：不能为空")
+
+	a.Equal(LocalizeError(err, language.English), err)
+	a.Equal(err.Error(), "error in field title of file config.yaml: can not be empty")
+
+	// 已经指定语言的不会被修改
+	LocalizeError(err, language.SimplifiedChinese)
+	a.Equal(err.Language, language.English)
+
+	// 未翻译的内容原样输出
+	err.Message = "100%"
+	a.Equal(err.Error(), "error in field title of file config.yaml: 100%")
+}
+
+func TestFieldError_Args(t *testing.T) {
+	a := assert.New(t)
+
+	err := &FieldError{File: "meta.yaml", Field: "extra.a", Message: "类型必须为 %s", Args: []interface{}{"int"}}
+	a.Equal(err.Error(), "在文件 meta.yaml 中的 extra.a 字段发生错误：类型必须为 int")
+
+	LocalizeError(err, language.English)
+	a.Equal(err.Error(), "error in field extra.a of file meta.yaml: type must be int")
+}
+
+func TestError(t *testing.T) {
+	a := assert.New(t)
+
+	err := Errorf("类型必须为 %s", "int")
+	a.Equal(err.Error(), "类型必须为 int")
+
+	a.Equal(LocalizeError(err, language.English), err)
+	a.Equal(err.Error(), "type must be int")
+
+	// 没有参数
+	a.Equal(LocalizeError(Errorf("缺少参数"), language.English).Error(), "missing argument")
+
+	// 参数中的 Error 以相同的语言输出
+	err = Errorf("第 %d 行：%s", 5, Errorf("参数 %s 缺少值", "src"))
+	a.Equal(err.Error(), "第 5 行：参数 src 缺少值")
+	LocalizeError(err, language.English)
+	a.Equal(err.Error(), "line 5: parameter src has no value")
+}
+
+func TestNewFieldError(t *testing.T) {
+	a := assert.New(t)
+
+	err := NewFieldError("content.html", "content", Errorf("第 %d 行：元素 <%s> 未闭合", 3, "div"))
+	a.Equal(err.Message, "第 %d 行：元素 <%s> 未闭合").
+		Equal(err.Args, []interface{}{3, "div"})
+	LocalizeError(err, language.English)
+	a.Equal(err.Error(), "error in field content of file content.html: line 3: element <div> is not closed")
+
+	// 普通的错误
+	err = NewFieldError("meta.yaml", "language", errors.New("100%"))
+	a.Equal(err.Message, "100%").Empty(err.Args)
+	a.Equal(err.Error(), "在文件 meta.yaml 中的 language 字段发生错误：100%")
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package locale 界面文字的本地化。
+//
+// 程序中的文字均以简体中文作为键名，其它语言的翻译内容位于 messages.go 中，
+// 找不到翻译内容时，原样输出键名。
+package locale
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// 程序内置的语言，第一个元素同时也是找不到匹配语言时的默认值。
+var tags = []language.Tag{
+	language.SimplifiedChinese,
+	language.English,
+}
+
+var matcher = language.NewMatcher(tags)
+
+// Locale 某一语言的本地化内容
+type Locale struct {
+	tag      language.Tag
+	messages map[string]string
+	printer  *message.Printer
+}
+
+// New 声明 tag 语言的 Locale 实例
+//
+// messages 为额外的翻译内容，比如主题中定义的，会覆盖程序内置的同名内容。
+// 程序未内置 tag 语言时，使用与其最接近的语言，都不接近则使用简体中文。
+func New(tag language.Tag, messages map[string]string) *Locale {
+	tag = tags[match(tag)]
+
+	msgs := make(map[string]string, len(builtin[tag])+len(messages))
+	for key, msg := range builtin[tag] {
+		msgs[key] = msg
+	}
+	for key, msg := range messages {
+		msgs[key] = msg
+	}
+
+	b := catalog.NewBuilder(catalog.Fallback(tag))
+	for key, msg := range msgs {
+		b.SetString(tag, key, msg)
+	}
+
+	return &Locale{
+		tag:      tag,
+		messages: msgs,
+		printer:  message.NewPrinter(tag, message.Catalog(b)),
+	}
+}
+
+// Tag 实际使用的语言
+func (l *Locale) Tag() language.Tag {
+	return l.tag
+}
+
+// Sprintf 获取 key 的翻译内容，并以 args 进行格式化。
+//
+// 没有 args 时，不对内容进行格式化，所以 key 中可以包含 % 等字符。
+func (l *Locale) Sprintf(key string, args ...interface{}) string {
+	if len(args) == 0 {
+		if msg, found := l.messages[key]; found {
+			return msg
+		}
+		return key
+	}
+
+	return l.printer.Sprintf(key, args...)
+}
+
+// Sprintf 以 tag 语言获取 key 的翻译内容，并以 args 进行格式化。
+func Sprintf(tag language.Tag, key string, args ...interface{}) string {
+	return locales[match(tag)].Sprintf(key, args...)
+}
+
+// 程序内置的各个语言的 Locale，与 tags 一一对应。
+var locales = make([]*Locale, 0, len(tags))
+
+func init() {
+	for _, tag := range tags {
+		locales = append(locales, New(tag, nil))
+	}
+}
+
+// 获取与 tag 最接近的内置语言在 tags 中的下标
+func match(tag language.Tag) int {
+	// language.Und 会被匹配为英语
+	if tag == language.Und {
+		return 0
+	}
+
+	_, index, _ := matcher.Match(tag)
+	return index
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package locale
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+	"golang.org/x/text/language"
+)
+
+func TestNew(t *testing.T) {
+	a := assert.New(t)
+
+	l := New(language.MustParse("zh-cmn-Hans"), nil)
+	a.Equal(l.Tag(), language.SimplifiedChinese)
+	a.Equal(l.Sprintf("标签"), "标签")
+
+	l = New(language.MustParse("en-US"), map[string]string{"阅读全文": "Read more", "标签": "Topics"})
+	a.Equal(l.Tag(), language.English)
+	a.Equal(l.Sprintf("阅读全文"), "Read more")
+	a.Equal(l.Sprintf("标签"), "Topics") // 覆盖内置的内容
+	a.Equal(l.Sprintf("归档"), "Archives")
+	a.Equal(l.Sprintf("标签：%content%"), "Tag: %content%")
+	a.Equal(l.Sprintf("不存在"), "不存在")
+	a.Equal(l.Sprintf("共 %d 篇", 5), "共 5 篇")
+
+	// 没有相近的语言
+	l = New(language.Japanese, nil)
+	a.Equal(l.Tag(), language.SimplifiedChinese)
+
+	l = New(language.Und, nil)
+	a.Equal(l.Tag(), language.SimplifiedChinese)
+}
+
+func TestSprintf(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(Sprintf(language.English, "在文件 %s 中的 %s 字段发生错误：%s", "f", "t", "m"), "error in field t of file f: m")
+	a.Equal(Sprintf(language.Chinese, "在文件 %s 中的 %s 字段发生错误：%s", "f", "t", "m"), "在文件 f 中的 t 字段发生错误：m")
+}
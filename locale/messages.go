@@ -0,0 +1,123 @@
+// Copyright 2018 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package locale
+
+import "golang.org/x/text/language"
+
+// 程序内置的翻译内容，键名即为简体中文的内容，所以简体中文不需要翻译。
+var builtin = map[language.Tag]map[string]string{
+	language.SimplifiedChinese: nil,
+	language.English:           en,
+}
+
+var en = map[string]string{
+	// 页面的默认标题
+	"标签：%content%": "Tag: %content%",
+	"标签":           "Tags",
+	"归档":           "Archives",
+	"归档：%content%": "Archives: %content%",
+	"搜索：%content%": "Search: %content%",
+	"友情链接":         "Links",
+	"作者：%content%": "Author: %content%",
+	"作者":           "Authors",
+
+	// 归档标题的默认格式
+	"2006 年":      "2006",
+	"2006 年 01 月": "January 2006",
+
+	// 加载数据时的错误信息
+	"在文件 %s 中的 %s 字段发生错误：%s": "error in field %[2]s of file %[1]s: %[3]s",
+	"不能为空":                     "can not be empty",
+	"不能小于 0":                   "can not be less than 0",
+	"必须大于 0":                   "must be greater than 0",
+	"必须为大于零的整数":                "must be a positive integer",
+	"取值不正确":                    "invalid value",
+	"无效的取值":                    "invalid value",
+	"无效的值":                     "invalid value",
+	"重复的值":                     "duplicate value",
+	"重复的语言":                    "duplicate language",
+	"介于[0,1]之间的浮点数":            "must be a float between 0 and 1",
+	"介于[1,100]之间的整数":           "must be an integer between 1 and 100",
+	"不能包含 {、}、? 和 # 等字符":       "can not contain {, }, ? or #",
+	"不是一个正确的 URL":              "not a valid URL",
+	"不是一个正确的 Email":            "not a valid email",
+	"状态码为 410 时不能指定该值":         "must be empty when the status is 410",
+	"未指定任何关联标签信息":              "no tags specified",
+	"无法根据扩展名获取，必须指定":           "can not be detected from the extension, must be specified",
+	"无效的状态码":                   "invalid status code",
+	"必须是包含域名的 http 或 https 地址": "must be an http or https URL with a host",
+	"必须指定作者":                   "author must be specified",
+	"必须以 / 开头":                 "must start with /",
+	"不能以 / 开头":                 "can not start with /",
+	"不能为空且只能以 / 开头":            "can not be empty and must start with /",
+	"只能以 / 开头，且必须有内容":          "must start with / and can not be /",
+	"以路径的形式分页时，必须以 / 结尾":       "must end with / when paging by path",
+	"存在循环跳转":                   "redirect loop detected",
+	"同一语言中存在相同的值":              "duplicate value in the same language",
+	"只能包含字母、数字和 -":             "can only contain letters, digits and -",
+	"仅 rss 支持该值":               "only supported by rss",
+	"与已有的路由冲突":                 "conflicts with an existing route",
+	"与其它重定向的地址重复":              "duplicates another redirect",
+	"不能与 source 相同":            "can not be the same as source",
+	"不存在的转换器":                  "unknown transformer",
+	"不存在的样式":                   "unknown style",
+	"与主题中的 highlight.css 冲突":   "conflicts with highlight.css in the theme",
+	"不能与 author 同时指定":          "can not be used together with author",
+	"不存在的作者":                   "unknown author",
+	"不存在的标签":                   "unknown tag",
+	"专题与标签不能相互嵌套":              "series and tags can not be nested in each other",
+	"存在循环引用":                   "circular reference detected",
+	"专题不能指定父标签":                "series can not have a parent",
+	"不能与 page 同时指定":            "can not be used together with page",
+	"不存在的页面":                   "unknown page",
+	"必须为密码的散列值":                "must be a password hash",
+	"不存在的分组":                   "unknown group",
+
+	// 带参数的错误信息
+	"图片 %s 的像素数超过了 images.maxPixels 的限制": "image %s exceeds the images.maxPixels limit",
+	"域名 %s 已经存在":                "host %s already exists",
+	"在专题 %s 中已经存在相同的序号 %d":      "part %[2]d already exists in series %[1]s",
+	"地址 %s 与其它页面冲突":             "url %s conflicts with another page",
+	"类型必须为 %s":                  "type must be %s",
+	"模板 %s 未定义":                 "template %s is not defined",
+	"已经存在同名的转换器：%s":             "transformer already exists: %s",
+	"存在同名的文章：%s":                "duplicate post: %s",
+	"存在同名的标签：%s":                "duplicate tag: %s",
+	"不支持的占位符 %s":                "unsupported placeholder %s",
+	"必须包含占位符 %s":                "must contain placeholder %s",
+	"与 permalinks.%s 冲突":        "conflicts with permalinks.%s",
+	"以查询参数的形式分页时，格式必须为 ?key=%s": "must be in the form ?key=%s when paging by query",
+
+	// 文章内容的错误信息
+	"第 %d 行：%s":             "line %d: %s",
+	"第 %d 行：元素 <%s> 未闭合":    "line %d: element <%s> is not closed",
+	"第 %d 行：元素 <%s> 不能自闭合":  "line %d: element <%s> can not be self-closing",
+	"第 %d 行：多余的结束标签 </%s>":  "line %d: unexpected end tag </%s>",
+	"第 %d 行：未定义的短代码 %s":     "line %d: undefined shortcode %s",
+	"第 %d 行：短代码 %s 执行出错：%s": "line %d: failed to execute shortcode %s: %s",
+	"第 %d 行：短代码缺少结束标记 %s":   "line %d: shortcode is missing the closing %s",
+	"第 %d 行：%s 没有对应的开始标签":   "line %d: %s has no opening tag",
+	"缺少参数 %s":               "missing parameter %s",
+	"缺少短代码名称":               "missing shortcode name",
+	"缺少参数名称":                "missing parameter name",
+	"参数 %s 缺少值":             "parameter %s has no value",
+	"参数 %s 的值缺少结束的引号":       "value of parameter %s is missing the closing quote",
+	"公式 %s 错误：%s":           "invalid formula %s: %s",
+	"多余的字符 %c":              "unexpected character %c",
+	"多余的 }":                 "unexpected }",
+	"重复的下标":                 "duplicate subscript",
+	"重复的上标":                 "duplicate superscript",
+	"缺少参数":                  "missing argument",
+	"缺少 }":                  "missing }",
+	"缺少 {":                  "missing {",
+	"缺少 ]":                  "missing ]",
+	"%c 之前缺少内容":             "missing content before %c",
+	"不支持的字符 %c":             "unsupported character %c",
+	`不支持的命令 \%s`:            `unsupported command \%s`,
+	`\left 缺少对应的 \right`:    `\left without matching \right`,
+	"缺少分隔符":                 "missing delimiter",
+	`无效的分隔符 \%s`:            `invalid delimiter \%s`,
+	"无效的分隔符 %c":             "invalid delimiter %c",
+}
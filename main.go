@@ -6,17 +6,21 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
 	"runtime"
+	"strings"
 
 	"github.com/issue9/logs"
 	"github.com/issue9/web"
 	"github.com/issue9/web/encoding"
-	"github.com/issue9/web/encoding/html"
 
 	"github.com/caixw/gitype/app"
+	"github.com/caixw/gitype/client/page"
+	"github.com/caixw/gitype/data/loader"
 	"github.com/caixw/gitype/path"
 	"github.com/caixw/gitype/vars"
 )
@@ -29,6 +33,7 @@ const usage = `%s 是一个基于 Git 的博客系统。
 
 %s -preview -appdir="./"
 %s -appdir="./"
+echo "password" | %s -password
 
 
 参数：
@@ -41,8 +46,9 @@ func main() {
 	preview := flag.Bool("preview", false, "是否启用预览模式")
 	appdir := flag.String("appdir", "./", "指定运行的工作目录")
 	init := flag.String("init", "", "初始化一个工作目录")
+	password := flag.Bool("password", false, "从标准输入读取密码，并输出受保护文章使用的散列值")
 	flag.Usage = func() {
-		fmt.Printf(usage, vars.Name, vars.URL, vars.Name, vars.Name)
+		fmt.Printf(usage, vars.Name, vars.URL, vars.Name, vars.Name, vars.Name)
 		flag.PrintDefaults()
 	}
 	flag.Parse()
@@ -60,6 +66,9 @@ func main() {
 		}
 		fmt.Printf("操作成功，你现在可以在 %s 中修改具体的参数配置！\n", *init)
 		return
+	case *password:
+		printPasswordHash()
+		return
 	}
 
 	path := path.New(*appdir)
@@ -72,8 +81,8 @@ func main() {
 		panic(err)
 	}
 
-	htmlMgr := html.New(nil)
-	if err := encoding.AddMarshal("text/html", htmlMgr.Marshal); err != nil {
+	// 页面使用各个网站自身的主题模板渲染
+	if err := encoding.AddMarshal("text/html", page.Marshal); err != nil {
 		panic(err)
 	}
 
@@ -82,7 +91,7 @@ func main() {
 		panic(err)
 	}
 
-	logs.Critical(app.Run(path, htmlMgr, *preview))
+	logs.Critical(app.Run(path, *preview))
 	logs.Flush()
 }
 
@@ -93,3 +102,17 @@ func printVersion() {
 		fmt.Println("Git commit hash:", vars.CommitHash())
 	}
 }
+
+// 输出密码的散列值，密码从标准输入读取，避免出现在命令行的历史记录中。
+func printPasswordHash() {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && len(line) == 0 {
+		panic(err)
+	}
+
+	hash, err := loader.HashPassword(strings.TrimRight(line, "\r\n"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(hash)
+}
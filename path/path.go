@@ -7,6 +7,7 @@ package path
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/caixw/gitype/vars"
 )
@@ -16,40 +17,49 @@ import (
 type Path struct {
 	Root string // 项目的根目录
 
-	ConfDir string // 项目下的配置文件所在目录
-	DataDir string // 项目下数据文件所在的目录，即 Git 数据所在的目录
+	ConfDir  string // 项目下的配置文件所在目录
+	DataDir  string // 项目下数据文件所在的目录，即 Git 数据所在的目录
+	CacheDir string // 程序生成的缓存文件所在的目录
 
 	// 数据目录下的子目录
 	PostsDir  string
 	ThemesDir string
 	MetaDir   string
 	RawsDir   string
+	PagesDir  string
 
-	MetaConfigFile string
-	MetaLinksFile  string
-	MetaTagsFile   string
+	MetaConfigFile    string
+	MetaLinksFile     string
+	MetaTagsFile      string
+	MetaRedirectsFile string
+	MetaAuthorsFile   string
 }
 
 // New 声明一个新的 Path
 func New(root string) *Path {
 	dataDir := filepath.Join(root, vars.DataFolderName)
 	confDir := filepath.Join(root, vars.ConfFolderName)
+	cacheDir := filepath.Join(root, vars.CacheFolderName)
 
 	p := &Path{
 		Root: root,
 
-		DataDir: dataDir,
-		ConfDir: confDir,
+		DataDir:  dataDir,
+		ConfDir:  confDir,
+		CacheDir: cacheDir,
 
 		PostsDir:  filepath.Join(dataDir, vars.PostsFolderName),
 		ThemesDir: filepath.Join(dataDir, vars.ThemesFolderName),
 		MetaDir:   filepath.Join(dataDir, vars.MetaFolderName),
 		RawsDir:   filepath.Join(dataDir, vars.RawsFolderName),
+		PagesDir:  filepath.Join(dataDir, vars.PagesFolderName),
 	}
 
 	p.MetaConfigFile = p.MetaPath(vars.ConfigFilename)
 	p.MetaLinksFile = p.MetaPath(vars.LinksFilename)
 	p.MetaTagsFile = p.MetaPath(vars.TagsFilename)
+	p.MetaRedirectsFile = p.MetaPath(vars.RedirectsFilename)
+	p.MetaAuthorsFile = p.MetaPath(vars.AuthorsFilename)
 
 	return p
 }
@@ -64,6 +74,11 @@ func (p *Path) RawsPath(file string) string {
 	return filepath.Join(p.RawsDir, file)
 }
 
+// ImagePath 获取 cache/images/ 下的文件
+func (p *Path) ImagePath(file string) string {
+	return filepath.Join(p.CacheDir, vars.ImagesFolderName, filepath.FromSlash(file))
+}
+
 // ThemesPath 返回指定主题下的指定文件
 func (p *Path) ThemesPath(path ...string) string {
 	paths := make([]string, 0, len(path)+1)
@@ -92,3 +107,54 @@ func (p *Path) PostMetaPath(slug string) string {
 func (p *Path) PostContentPath(slug string) string {
 	return p.PostPath(slug, vars.PostContentFilename)
 }
+
+// PostLanguageMetaPath 返回某一篇文章下 lang 语言版本的 meta.yaml 文件地址，比如 meta.en.yaml
+func (p *Path) PostLanguageMetaPath(slug, lang string) string {
+	return p.PostPath(slug, languageFilename(vars.PostMetaFilename, lang))
+}
+
+// PostLanguageContentPath 返回某一篇文章下 lang 语言版本的内容的文件地址，比如 content.en.html
+func (p *Path) PostLanguageContentPath(slug, lang string) string {
+	return p.PostPath(slug, languageFilename(vars.PostContentFilename, lang))
+}
+
+// PagePath 返回某一独立页面下的文件名
+func (p *Path) PagePath(slug, filename string) string {
+	slug = filepath.FromSlash(slug)
+	return filepath.Join(p.PagesDir, slug, filename)
+}
+
+// PageMetaPath 返回某一独立页面下的 meta.yaml 文件地址
+func (p *Path) PageMetaPath(slug string) string {
+	return p.PagePath(slug, vars.PostMetaFilename)
+}
+
+// PageContentPath 返回某一独立页面下的内容的文件地址
+func (p *Path) PageContentPath(slug string) string {
+	return p.PagePath(slug, vars.PostContentFilename)
+}
+
+// IsPostFile 判断 filename 是否为文章的 meta.yaml 或是 content.html，
+// 包括 meta.{lang}.yaml 和 content.{lang}.html 等各个语言版本。
+func IsPostFile(filename string) bool {
+	for _, name := range []string{vars.PostMetaFilename, vars.PostContentFilename} {
+		if filename == name {
+			return true
+		}
+
+		ext := filepath.Ext(name)
+		prefix := strings.TrimSuffix(name, ext) + "."
+		if strings.HasPrefix(filename, prefix) && strings.HasSuffix(filename, ext) &&
+			len(filename) > len(prefix)+len(ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 在文件名的扩展名之前插入语言标签
+func languageFilename(filename, lang string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "." + lang + ext
+}
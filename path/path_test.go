@@ -17,8 +17,29 @@ func TestPath(t *testing.T) {
 	p := New("/")
 	a.Equal(p.ConfDir, "/"+vars.ConfFolderName)
 
+	a.Equal(p.ImagePath("posts/1.png"), "/"+vars.CacheFolderName+"/"+vars.ImagesFolderName+"/posts/1.png")
+
 	// ThemesPath
 	a.Equal(p.ThemesPath("def", "//style", "style.png"), "/data/themes/def/style/style.png")
 	a.Equal(p.ThemesPath("def", "//style//style.png"), "/data/themes/def/style/style.png")
 	a.Equal(p.ThemesPath("def", "//style//*.html"), "/data/themes/def/style/*.html")
+
+	// 语言版本
+	a.Equal(p.PostLanguageMetaPath("folder/post", "en"), "/data/posts/folder/post/meta.en.yaml")
+	a.Equal(p.PostLanguageContentPath("folder/post", "en"), "/data/posts/folder/post/content.en.html")
+}
+
+func TestIsPostFile(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(IsPostFile("meta.yaml"))
+	a.True(IsPostFile("content.html"))
+	a.True(IsPostFile("meta.en.yaml"))
+	a.True(IsPostFile("content.zh-Hans.html"))
+
+	a.False(IsPostFile("meta.html"))
+	a.False(IsPostFile("content.yaml"))
+	a.False(IsPostFile("content..html"))
+	a.False(IsPostFile("cover.png"))
+	a.False(IsPostFile("metadata.yaml"))
 }
@@ -10,39 +10,6 @@ import (
 	"github.com/issue9/assert"
 )
 
-func TestPostURL(t *testing.T) {
-	a := assert.New(t)
-
-	a.Equal(PostURL("1"), "/posts/1.html")
-}
-
-func TestPostsURL(t *testing.T) {
-	a := assert.New(t)
-
-	a.Equal(PostsURL(0), "/")
-	a.Equal(PostsURL(1), "/")
-	a.Equal(PostsURL(2), "/index.html?"+URLQueryPage+"=2")
-}
-
-func TestTagURL(t *testing.T) {
-	a := assert.New(t)
-	a.Equal(TagURL("1", 0), "/tags/1.html")
-	a.Equal(TagURL("1", 1), "/tags/1.html")
-	a.Equal(TagURL("1", 2), "/tags/1.html?"+URLQueryPage+"=2")
-}
-
-func TestSearchURL(t *testing.T) {
-	a := assert.New(t)
-
-	a.Equal(SearchURL("", 0), "/search.html")
-	a.Equal(SearchURL("", 1), "/search.html")
-	a.Equal(SearchURL("", 2), "/search.html?"+URLQueryPage+"=2")
-
-	a.Equal(SearchURL("q", 0), "/search.html?"+URLQuerySearch+"=q")
-	a.Equal(SearchURL("q", 1), "/search.html?"+URLQuerySearch+"=q")
-	a.Equal(SearchURL("q", 2), "/search.html?q=q&amp;"+URLQueryPage+"=2")
-}
-
 func TestThemesURL(t *testing.T) {
 	a := assert.New(t)
 
@@ -60,3 +27,11 @@ func TestAssetURL(t *testing.T) {
 	a.Equal(AssetURL("/abc.png"), "/posts/abc.png")
 	a.Equal(AssetURL("abc.png"), "/posts/abc.png")
 }
+
+func TestImageURL(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(ImageURL(""), "/images/")
+	a.Equal(ImageURL("/abc.png"), "/images/abc.png")
+	a.Equal(ImageURL("abc.png"), "/images/abc.png")
+}
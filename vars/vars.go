@@ -36,17 +36,27 @@ const (
 	// OutdatedFrequency outdated 的更新频率。
 	// NOTE: 此值过小，有可能会影响服务器性能
 	OutdatedFrequency = time.Hour * 24
+
+	// WordsPerMinute 每分钟的阅读字数，用于计算文章的阅读时间。
+	// 中日韩文字每个字计为一个字，其它文字以单词计。
+	WordsPerMinute = 300
 )
 
 // 目录名称的定义
 const (
-	DataFolderName = "data"
-	ConfFolderName = "conf"
+	DataFolderName  = "data"
+	ConfFolderName  = "conf"
+	CacheFolderName = "cache" // 程序生成的缓存内容，不能放在 data 目录下
+
+	ImagesFolderName = "images" // 缓存目录下保存图片的目录
 
 	PostsFolderName  = "posts"
 	ThemesFolderName = "themes"
 	MetaFolderName   = "meta"
 	RawsFolderName   = "raws"
+	PagesFolderName  = "pages" // 独立页面所在的目录，可以不存在
+
+	ShortcodesFolderName = "shortcodes" // 主题目录下保存短代码模板的目录
 )
 
 // 文件名的定义
@@ -55,10 +65,22 @@ const (
 	TagsFilename   = "tags.yaml"
 	LinksFilename  = "links.yaml"
 
+	// 重定向的配置文件，可以不存在
+	RedirectsFilename = "redirects.yaml"
+
+	// 作者列表，可以不存在
+	AuthorsFilename = "authors.yaml"
+
 	PostMetaFilename    = "meta.yaml"
 	PostContentFilename = "content.html"
 
 	ThemeMetaFilename = "theme.yaml"
+
+	// 代码高亮的样式表文件名，由程序根据配置生成，位于 themes 之下
+	HighlightFilename = "highlight.css"
+
+	// 受保护文章 cookie 的签名密钥，由程序生成，位于 conf 之下
+	SecretFilename = "secret.key"
 )
 
 // 页面的类型，除了 PageIndex 其它的同时也是模板名称。
@@ -69,8 +91,15 @@ const (
 	PageTags     = "tags"
 	PageTag      = "tag"
 	PageArchives = "archives"
+	PageArchive  = "archive" // 按年或是按月的存档页
 	PageLinks    = "links"
 	PageSearch   = "search"
+	PageAuthors  = "authors" // 作者列表页，仅在存在 authors.yaml 时才需要该模板
+	PageAuthor   = "author"  // 作者详细页，仅在存在 authors.yaml 时才需要该模板
+	PageSingle   = "page"    // pages 目录下的独立页面，仅在存在独立页面时才需要该模板
+
+	// 受保护文章的密码输入页，仅在存在受保护的文章时才需要该模板
+	PageProtected = "protected"
 )
 
 // Etag 根据一个时间，生成一段 Etag 字符串
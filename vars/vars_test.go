@@ -18,13 +18,6 @@ func TestTemplateExtension(t *testing.T) {
 	a.True(len(TemplateExtension) > 2)
 }
 
-func TestURLSuffix(t *testing.T) {
-	a := assert.New(t)
-
-	a.Equal(urlSuffix[0], '.')
-	a.True(len(urlSuffix) > 2)
-}
-
 func TestURL(t *testing.T) {
 	a := assert.New(t)
 